@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"onchain-energe-SRSI/geckoterminal"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	adminMaxBody      = 1 << 20
+	adminSignatureTTL = 5 * time.Minute // 签名时间戳允许的偏差
+)
+
+// registerAdminRoutes 管理接口，均需鉴权并写审计日志
+func registerAdminRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/admin/ban", adminRoute(http.MethodPost, "ban", adminBan))
+	mux.HandleFunc("/api/admin/unban", adminRoute(http.MethodPost, "unban", adminUnban))
+	mux.HandleFunc("/api/admin/watch", adminRoute(http.MethodPost, "watch", adminWatch))
+	mux.HandleFunc("/api/admin/unwatch", adminRoute(http.MethodPost, "unwatch", adminUnwatch))
+	mux.HandleFunc("/api/admin/pause", adminRoute(http.MethodPost, "pause", func(context.Context, []byte) (any, error) {
+		scannerPaused.Store(true)
+		return map[string]bool{"paused": true}, nil
+	}))
+	mux.HandleFunc("/api/admin/resume", adminRoute(http.MethodPost, "resume", func(context.Context, []byte) (any, error) {
+		scannerPaused.Store(false)
+		return map[string]bool{"paused": false}, nil
+	}))
+	mux.HandleFunc("/api/admin/scan-now", adminRoute(http.MethodPost, "scan-now", adminScanNow))
+	mux.HandleFunc("/api/admin/filters", adminRoute(http.MethodPut, "filters", adminFilters))
+	mux.HandleFunc("/api/admin/kill-switch", adminRoute(http.MethodPost, "kill-switch", adminKillSwitch))
+}
+
+// httpError 携带状态码的接口错误
+type httpError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *httpError) Error() string { return e.message }
+
+func badRequest(format string, args ...any) error {
+	return &httpError{http.StatusBadRequest, "invalid_argument", fmt.Sprintf(format, args...)}
+}
+
+// adminRoute 校验方法与鉴权，执行操作并写审计日志
+func adminRoute(method, action string, fn func(ctx context.Context, body []byte) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "仅支持 "+method)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, adminMaxBody))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_argument", "读取请求体失败")
+			return
+		}
+
+		authMethod, he := authenticateAdmin(r, body)
+		if he != nil {
+			writeAudit(r, action, "", body, he.status, he.message)
+			writeError(w, he.status, he.code, he.message)
+			return
+		}
+
+		resp, err := fn(r.Context(), body)
+		if err != nil {
+			he, ok := err.(*httpError)
+			if !ok {
+				he = &httpError{http.StatusInternalServerError, "internal", err.Error()}
+			}
+			writeAudit(r, action, authMethod, body, he.status, he.message)
+			writeError(w, he.status, he.code, he.message)
+			return
+		}
+		writeAudit(r, action, authMethod, body, http.StatusOK, "")
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// authenticateAdmin 支持两种方式：
+//   - Authorization: Bearer <admin.token>
+//   - X-Timestamp: unix 秒；X-Signature: sha256=hex(HMAC(admin.hmac_secret, timestamp + "." + METHOD + " " + path + "." + body))
+func authenticateAdmin(r *http.Request, body []byte) (string, *httpError) {
+	cfg := config.Admin
+	if cfg.Token == "" && cfg.HMACSecret == "" {
+		return "", &httpError{http.StatusForbidden, "admin_disabled", "管理接口未启用"}
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && cfg.Token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Token)) == 1 {
+			return "bearer", nil
+		}
+		return "", &httpError{http.StatusUnauthorized, "unauthorized", "token 无效"}
+	}
+
+	if sig := r.Header.Get("X-Signature"); sig != "" && cfg.HMACSecret != "" {
+		ts := r.Header.Get("X-Timestamp")
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return "", &httpError{http.StatusUnauthorized, "unauthorized", "X-Timestamp 无效"}
+		}
+		if d := time.Since(time.Unix(sec, 0)); d > adminSignatureTTL || d < -adminSignatureTTL {
+			return "", &httpError{http.StatusUnauthorized, "unauthorized", "签名已过期"}
+		}
+		expected := "sha256=" + adminSignature(cfg.HMACSecret, ts, r.Method, r.URL.Path, body)
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return "hmac", nil
+		}
+		return "", &httpError{http.StatusUnauthorized, "unauthorized", "签名无效"}
+	}
+
+	return "", &httpError{http.StatusUnauthorized, "unauthorized", "缺少鉴权信息"}
+}
+
+// adminSignature 管理接口请求签名
+func adminSignature(secret, timestamp, method, path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%s.%s %s.", timestamp, method, path)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// auditEntry 审计日志（JSON Lines）
+type auditEntry struct {
+	Time   time.Time       `json:"time"`
+	Remote string          `json:"remote"`
+	Action string          `json:"action"`
+	Auth   string          `json:"auth,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Status int             `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+var auditLog struct {
+	sync.Mutex
+	file *os.File
+}
+
+func writeAudit(r *http.Request, action, auth string, body []byte, status int, errMsg string) {
+	e := auditEntry{
+		Time:   time.Now(),
+		Remote: r.RemoteAddr,
+		Action: action,
+		Auth:   auth,
+		Status: status,
+		Error:  errMsg,
+	}
+	if json.Valid(body) {
+		e.Params = json.RawMessage(bytes.TrimSpace(body))
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	logging.Component("admin").Info("管理操作", "action", action, "auth", auth, "remote", e.Remote, "status", status, "err", errMsg)
+
+	auditLog.Lock()
+	defer auditLog.Unlock()
+	if auditLog.file == nil {
+		if err := os.MkdirAll(filepath.Dir(config.Admin.AuditLog), 0o755); err != nil {
+			logging.Component("admin").Error("创建审计日志目录失败", "err", err)
+			return
+		}
+		f, err := os.OpenFile(config.Admin.AuditLog, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			logging.Component("admin").Error("打开审计日志失败", "err", err)
+			return
+		}
+		auditLog.file = f
+	}
+	auditLog.file.Write(append(line, '\n'))
+}
+
+// closeAuditLog 退出时关闭审计日志
+func closeAuditLog() {
+	auditLog.Lock()
+	defer auditLog.Unlock()
+	if auditLog.file != nil {
+		auditLog.file.Close()
+		auditLog.file = nil
+	}
+}
+
+type addressRequest struct {
+	Address string `json:"address"`
+}
+
+func parseAddress(body []byte) (string, error) {
+	var req addressRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", badRequest("请求体需为 JSON: %v", err)
+	}
+	if req.Address = strings.TrimSpace(req.Address); req.Address == "" {
+		return "", badRequest("缺少 address")
+	}
+	return req.Address, nil
+}
+
+func adminBan(_ context.Context, body []byte) (any, error) {
+	addr, err := parseAddress(body)
+	if err != nil {
+		return nil, err
+	}
+	banAddress(addr)
+	return map[string]any{"banned": bannedAddresses()}, nil
+}
+
+func adminUnban(_ context.Context, body []byte) (any, error) {
+	addr, err := parseAddress(body)
+	if err != nil {
+		return nil, err
+	}
+	if !unbanAddress(addr) {
+		return nil, &httpError{http.StatusNotFound, "not_found", "未在手动封禁列表中: " + addr}
+	}
+	return map[string]any{"banned": bannedAddresses()}, nil
+}
+
+func adminWatch(ctx context.Context, body []byte) (any, error) {
+	addr, err := parseAddress(body)
+	if err != nil {
+		return nil, err
+	}
+	pool, symbol, err := geckoterminal.GetTopPool(ctx, "solana", addr, config.Proxy)
+	if err != nil {
+		return nil, &httpError{http.StatusBadGateway, "upstream_error", fmt.Sprintf("查询交易池失败: %v", err)}
+	}
+	item := types.TokenItem{Chain: "solana", Address: addr, Symbol: symbol, PoolAddress: pool, Emoje: "👀"}
+	watchToken(item)
+	return item, nil
+}
+
+func adminUnwatch(_ context.Context, body []byte) (any, error) {
+	addr, err := parseAddress(body)
+	if err != nil {
+		return nil, err
+	}
+	if !unwatchToken(addr) {
+		return nil, &httpError{http.StatusNotFound, "not_found", "未在关注列表中: " + addr}
+	}
+	return map[string]string{"unwatched": addr}, nil
+}
+
+// adminScanNow 上一轮仍在执行时返回 409
+func adminScanNow(context.Context, []byte) (any, error) {
+	if atomic.LoadInt32(&runScanRunning) == 1 {
+		return nil, &httpError{http.StatusConflict, "scan_running", "上一轮扫描尚未结束"}
+	}
+	select {
+	case scanNowCh <- struct{}{}:
+		return map[string]string{"status": "queued"}, nil
+	default:
+		return nil, &httpError{http.StatusConflict, "scan_queued", "已有待执行的扫描"}
+	}
+}
+
+// adminFilters 请求体中未出现的字段保持当前值
+func adminFilters(_ context.Context, body []byte) (any, error) {
+	f := currentFilters()
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, badRequest("过滤条件解析失败: %v", err)
+	}
+	if f.MinMarketCapSol < 0 || f.MinHolders < 0 || f.MinLiquiditySol < 0 || f.MinBuyCount < 0 {
+		return nil, badRequest("阈值不能为负数")
+	}
+	if f.MaxTop10Holders <= 0 || f.MaxTop10Holders > 100 {
+		return nil, badRequest("max_top10_holders 需在 (0, 100] 之间")
+	}
+	setFilters(f)
+	return f, nil
+}
+
+// adminCORS 管理接口只允许配置的来源跨域，其余来源直接拒绝
+func adminCORS(w http.ResponseWriter, r *http.Request) (handled bool) {
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		if !slices.Contains(config.Admin.AllowedOrigins, origin) {
+			writeError(w, http.StatusForbidden, "origin_not_allowed", "来源不允许: "+origin)
+			return true
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", "POST, PUT, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Signature, X-Timestamp")
+	}
+	w.Header().Add("Vary", "Origin")
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/telegram"
+	"onchain-energe-SRSI/types"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 分页参数
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// newAPIMux 注册 HTTP 接口
+func newAPIMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/latest-tg-messages", getOnly(latestMessagesHandler))
+	mux.HandleFunc("/api/signal-stats", getOnly(signalStatsHandler))
+	mux.HandleFunc("/api/signal-outcomes", getOnly(signalOutcomesHandler))
+	mux.HandleFunc("/api/stream", events.ServeSSE)
+	mux.HandleFunc("/api/ws", events.ServeWS)
+	mux.HandleFunc("/metrics", getOnly(metrics.Handler))
+	mux.HandleFunc("/healthz", getOnly(healthzHandler))
+	mux.HandleFunc("/readyz", getOnly(readyzHandler))
+
+	mux.HandleFunc("/api/tokens", getOnly(tokensHandler))
+	mux.HandleFunc("/api/tokens/{address}", getOnly(tokenHandler))
+	mux.HandleFunc("/api/signals", getOnly(signalsHandler))
+	mux.HandleFunc("/api/scans", getOnly(scansHandler))
+	mux.HandleFunc("/api/config", getOnly(configHandler))
+	mux.HandleFunc("/api/paper", getOnly(paperHandler))
+	mux.HandleFunc("/api/paper/trades", getOnly(paperTradesHandler))
+	mux.HandleFunc("/api/paper/equity", getOnly(paperEquityHandler))
+	mux.HandleFunc("/api/execution", getOnly(executionHandler))
+	registerAdminRoutes(mux)
+
+	// 其余 /api/ 路径统一返回 JSON 404
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "接口不存在: "+r.URL.Path)
+	})
+	return mux
+}
+
+// apiError 统一的错误响应体
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	var e apiError
+	e.Error.Code = code
+	e.Error.Message = message
+	writeJSON(w, status, e)
+}
+
+// getOnly 非 GET 请求返回 JSON 405
+func getOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "仅支持 GET")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// page 分页结果
+type page[T any] struct {
+	Items      []T `json:"items"`
+	Total      int `json:"total"`
+	Limit      int `json:"limit"`
+	Offset     int `json:"offset"`
+	NextOffset int `json:"next_offset,omitempty"` // 0 表示没有下一页
+}
+
+// parsePage 解析 ?limit=&offset=
+func parsePage(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageLimit
+	q := r.URL.Query()
+	if l := q.Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+			return 0, 0, fmt.Errorf("limit 必须为正整数")
+		}
+		limit = min(limit, maxPageLimit)
+	}
+	if o := q.Get("offset"); o != "" {
+		if offset, err = strconv.Atoi(o); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset 必须为非负整数")
+		}
+	}
+	return limit, offset, nil
+}
+
+func paginate[T any](items []T, limit, offset int) page[T] {
+	p := page[T]{Items: []T{}, Total: len(items), Limit: limit, Offset: offset}
+	if offset < len(items) {
+		end := min(offset+limit, len(items))
+		p.Items = items[offset:end]
+		if end < len(items) {
+			p.NextOffset = end
+		}
+	}
+	return p
+}
+
+// parseSince 支持 RFC3339 或 unix 秒
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("since 需为 RFC3339 或 unix 秒")
+	}
+	return t, nil
+}
+
+// tokenView 扫描器中的代币状态
+type tokenView struct {
+	Symbol        string              `json:"symbol"`
+	TokenItem     types.TokenItem     `json:"token_item"`
+	LastEvaluated time.Time           `json:"last_evaluated"`
+	Banned        bool                `json:"banned"`
+	HolderGrowth  *types.HolderGrowth `json:"holder_growth,omitempty"`   // 启用持币补充且增长窗口内已有更早记录时返回
+	Diagnosis     *types.Diagnosis    `json:"diagnosis,omitempty"`       // 仅详情接口返回
+	ShortDiag     *types.Diagnosis    `json:"short_diagnosis,omitempty"` // 启用空头级联时返回
+}
+
+func snapshotTokens() []*types.TokenData {
+	tokenDataMutex.Lock()
+	defer tokenDataMutex.Unlock()
+	res := make([]*types.TokenData, 0, len(tokenDataMap))
+	for _, d := range tokenDataMap {
+		res = append(res, d)
+	}
+	return res
+}
+
+func viewToken(d *types.TokenData, withDiag bool) tokenView {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	v := tokenView{
+		Symbol:        d.Symbol,
+		TokenItem:     d.TokenItem,
+		LastEvaluated: d.LastUpdated,
+	}
+	v.Banned = isBanned(&v.TokenItem)
+	if d.Holders.Known {
+		growth := d.Holders
+		v.HolderGrowth = &growth
+	}
+	if withDiag {
+		diag := d.Diag
+		v.Diagnosis = &diag
+		if !d.ShortDiag.Time.IsZero() {
+			shortDiag := d.ShortDiag
+			v.ShortDiag = &shortDiag
+		}
+	}
+	return v
+}
+
+// tokensHandler GET /api/tokens
+func tokensHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	var views []tokenView
+	for _, d := range snapshotTokens() {
+		views = append(views, viewToken(d, false))
+	}
+	slices.SortFunc(views, func(a, b tokenView) int { return strings.Compare(a.Symbol, b.Symbol) })
+	writeJSON(w, http.StatusOK, paginate(views, limit, offset))
+}
+
+// tokenHandler GET /api/tokens/{address}
+func tokenHandler(w http.ResponseWriter, r *http.Request) {
+	addr := r.PathValue("address")
+	for _, d := range snapshotTokens() {
+		d.Mutex.Lock()
+		match := strings.EqualFold(d.TokenItem.Address, addr)
+		d.Mutex.Unlock()
+		if match {
+			writeJSON(w, http.StatusOK, viewToken(d, true))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "not_found", "未找到代币: "+addr)
+}
+
+// signalsHandler GET /api/signals?since=&strategy=&limit=&offset=，最新在前
+func signalsHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	since, err := parseSince(r.URL.Query().Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	strategy := r.URL.Query().Get("strategy")
+
+	signalHistory.RLock()
+	var res []types.Signal
+	for i := len(signalHistory.signals) - 1; i >= 0; i-- {
+		s := signalHistory.signals[i]
+		if s.Time.Before(since) {
+			break
+		}
+		if strategy == "" || s.Strategy == strategy {
+			res = append(res, s)
+		}
+	}
+	signalHistory.RUnlock()
+	writeJSON(w, http.StatusOK, paginate(res, limit, offset))
+}
+
+// scansHandler GET /api/scans?limit=&offset=，最新在前
+func scansHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	scanHistory.RLock()
+	res := make([]scanRecord, len(scanHistory.records))
+	for i, rec := range scanHistory.records {
+		res[len(res)-1-i] = rec
+	}
+	scanHistory.RUnlock()
+	writeJSON(w, http.StatusOK, paginate(res, limit, offset))
+}
+
+// configHandler GET /api/config，密钥已隐藏
+func configHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, redactedConfig())
+}
+
+// parseLimit 解析 ?limit=，未传时为 def
+func parseLimit(r *http.Request, def int) (int, error) {
+	l := r.URL.Query().Get("limit")
+	if l == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(l)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("limit 必须为正整数")
+	}
+	return v, nil
+}
+
+// latestMessagesHandler GET /api/latest-tg-messages?limit=，默认 25 条
+func latestMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r, 25)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, telegram.GetLatestMessages(limit))
+}
+
+// signalStatsHandler GET /api/signal-stats
+func signalStatsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, outcomeTracker.Stats())
+}
+
+// signalOutcomesHandler GET /api/signal-outcomes?limit=，默认 25 条，最新在前
+func signalOutcomesHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r, 25)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, outcomeTracker.Recent(limit))
+}
+
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/admin/") {
+			if !adminCORS(w, r) {
+				next.ServeHTTP(w, r)
+			}
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
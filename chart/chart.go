@@ -0,0 +1,261 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Line 叠加在价格区的指标线，长度与 Candles 一致
+type Line struct {
+	Label  string
+	Values []float64
+	Color  color.Color
+}
+
+// Panel 一个周期的图：K 线 + 叠加线 + MACD 副图
+type Panel struct {
+	Title    string
+	Candles  []geckoterminal.OHLCV // 时间升序
+	Overlays []Line
+	DIF      []float64
+	DEA      []float64
+	Hist     []float64
+}
+
+const (
+	width       = 960
+	titleHeight = 22
+	priceHeight = 300
+	macdHeight  = 110
+	panelHeight = titleHeight + priceHeight + macdHeight + 12
+	padLeft     = 8
+	padRight    = 80 // 右侧价格刻度
+	maxBars     = 90 // 每个面板最多显示的 K 线数量
+)
+
+var (
+	colorBG    = color.RGBA{0x13, 0x17, 0x22, 0xff}
+	colorGrid  = color.RGBA{0x2a, 0x2e, 0x39, 0xff}
+	colorText  = color.RGBA{0xd1, 0xd4, 0xdc, 0xff}
+	colorUp    = color.RGBA{0x26, 0xa6, 0x9a, 0xff}
+	colorDown  = color.RGBA{0xef, 0x53, 0x50, 0xff}
+	colorDIF   = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	colorDEA   = color.RGBA{0xff, 0x98, 0x00, 0xff}
+	ColorEMA25 = color.RGBA{0xff, 0xeb, 0x3b, 0xff}
+	ColorMA60  = color.RGBA{0xab, 0x47, 0xbc, 0xff}
+)
+
+// RenderPNG 将多个面板自上而下绘制为一张 PNG
+func RenderPNG(panels ...Panel) ([]byte, error) {
+	if len(panels) == 0 {
+		return nil, fmt.Errorf("没有可绘制的面板")
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, panelHeight*len(panels)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{colorBG}, image.Point{}, draw.Src)
+
+	for i, p := range panels {
+		if err := drawPanel(img, p, i*panelHeight); err != nil {
+			return nil, fmt.Errorf("绘制 %s 失败: %w", p.Title, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("PNG 编码失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func drawPanel(img *image.RGBA, p Panel, top int) error {
+	n := len(p.Candles)
+	if n == 0 {
+		return fmt.Errorf("K 线为空")
+	}
+	from := max(n-maxBars, 0)
+	candles := p.Candles[from:]
+	bars := len(candles)
+
+	plotW := width - padLeft - padRight
+	step := float64(plotW) / float64(bars)
+	bodyW := max(int(step*0.7), 1)
+	xOf := func(i int) int { return padLeft + int((float64(i)+0.5)*step) }
+
+	// 标题与图例
+	title := p.Title
+	for _, l := range p.Overlays {
+		title += "  " + l.Label
+	}
+	drawText(img, padLeft, top+15, title, colorText)
+
+	// 价格区
+	priceTop, priceBottom := top+titleHeight, top+titleHeight+priceHeight
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, c := range candles {
+		lo, hi = math.Min(lo, c.Low), math.Max(hi, c.High)
+	}
+	for _, l := range p.Overlays {
+		for _, v := range tail(l.Values, from) {
+			if v > 0 {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+	}
+	yPrice := scaler(lo, hi, priceTop+4, priceBottom-4)
+
+	for g := 0; g <= 4; g++ {
+		v := lo + (hi-lo)*float64(g)/4
+		y := yPrice(v)
+		hline(img, padLeft, width-padRight, y, colorGrid)
+		drawText(img, width-padRight+4, y+4, formatPrice(v), colorText)
+	}
+
+	for i, c := range candles {
+		col := colorUp
+		if c.Close < c.Open {
+			col = colorDown
+		}
+		x := xOf(i)
+		vline(img, x, yPrice(c.High), yPrice(c.Low), col)
+		y0, y1 := yPrice(c.Open), yPrice(c.Close)
+		fillRect(img, x-bodyW/2, min(y0, y1), x-bodyW/2+bodyW, max(y0, y1)+1, col)
+	}
+	for _, l := range p.Overlays {
+		polyline(img, tail(l.Values, from), xOf, yPrice, l.Color)
+	}
+
+	// MACD 副图
+	macdTop, macdBottom := priceBottom+8, priceBottom+8+macdHeight
+	dif, dea, hist := tail(p.DIF, from), tail(p.DEA, from), tail(p.Hist, from)
+	mlo, mhi := 0.0, 0.0
+	for _, s := range [][]float64{dif, dea, hist} {
+		for _, v := range s {
+			mlo, mhi = math.Min(mlo, v), math.Max(mhi, v)
+		}
+	}
+	yMACD := scaler(mlo, mhi, macdTop, macdBottom)
+	hline(img, padLeft, width-padRight, yMACD(0), colorGrid)
+	drawText(img, width-padRight+4, macdTop+10, "MACD", colorText)
+	for i, v := range hist {
+		col := colorUp
+		if v < 0 {
+			col = colorDown
+		}
+		x := xOf(i)
+		y0, y1 := yMACD(0), yMACD(v)
+		fillRect(img, x-bodyW/2, min(y0, y1), x-bodyW/2+bodyW, max(y0, y1)+1, col)
+	}
+	polyline(img, dif, xOf, yMACD, colorDIF)
+	polyline(img, dea, xOf, yMACD, colorDEA)
+	return nil
+}
+
+// tail 与 Candles 对齐截取，长度不足时返回 nil
+func tail(values []float64, from int) []float64 {
+	if from >= len(values) {
+		return nil
+	}
+	return values[from:]
+}
+
+// scaler 把数值映射到 [top,bottom] 像素区间（数值越大越靠上）
+func scaler(lo, hi float64, top, bottom int) func(float64) int {
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return func(v float64) int {
+		return bottom - int((v-lo)/(hi-lo)*float64(bottom-top))
+	}
+}
+
+func formatPrice(v float64) string {
+	switch {
+	case v >= 100:
+		return fmt.Sprintf("%.2f", v)
+	case v >= 1:
+		return fmt.Sprintf("%.4f", v)
+	default:
+		return fmt.Sprintf("%.3g", v)
+	}
+}
+
+func drawText(img *image.RGBA, x, y int, s string, col color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(col),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, col color.Color) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), &image.Uniform{col}, image.Point{}, draw.Src)
+}
+
+func hline(img *image.RGBA, x0, x1, y int, col color.Color) {
+	for x := x0; x < x1; x++ {
+		img.Set(x, y, col)
+	}
+}
+
+func vline(img *image.RGBA, x, y0, y1 int, col color.Color) {
+	for y := min(y0, y1); y <= max(y0, y1); y++ {
+		img.Set(x, y, col)
+	}
+}
+
+// polyline 连接相邻点，NaN（指标预热期）不绘制
+func polyline(img *image.RGBA, values []float64, xOf func(int) int, yOf func(float64) int, col color.Color) {
+	for i := 1; i < len(values); i++ {
+		a, b := values[i-1], values[i]
+		if math.IsNaN(a) || math.IsNaN(b) {
+			continue
+		}
+		line(img, xOf(i-1), yOf(a), xOf(i), yOf(b), col)
+	}
+}
+
+// line Bresenham 直线
+func line(img *image.RGBA, x0, y0, x1, y1 int, col color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		img.Set(x0, y0, col)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"onchain-energe-SRSI/geckoterminal"
+	"onchain-energe-SRSI/telegram"
+	"onchain-energe-SRSI/types"
+	"slices"
+	"strings"
+	"time"
+)
+
+// registerCommands 注册 Telegram 交互命令
+func registerCommands(bot *telegram.Bot) {
+	bot.Handle("/status", cmdStatus)
+	bot.Handle("/ban", cmdBan)
+	bot.Handle("/unban", cmdUnban)
+	bot.Handle("/watch", cmdWatch)
+	bot.Handle("/unwatch", cmdUnwatch)
+	bot.Handle("/diag", cmdDiag)
+	bot.Handle("/pause", func([]string) string {
+		scannerPaused.Store(true)
+		return "⏸ 扫描已暂停"
+	})
+	bot.Handle("/resume", func([]string) string {
+		scannerPaused.Store(false)
+		return "▶️ 扫描已恢复"
+	})
+	bot.Handle("/config", func([]string) string {
+		data, err := json.MarshalIndent(redactedConfig(), "", "  ")
+		if err != nil {
+			return fmt.Sprintf("序列化配置失败: %v", err)
+		}
+		return string(data)
+	})
+}
+
+func cmdStatus([]string) string {
+	scanStatus.Lock()
+	s := scanStatus.scanSummary
+	scanStatus.Unlock()
+
+	state := "运行中"
+	if scannerPaused.Load() {
+		state = "已暂停"
+	}
+	watchlist.RLock()
+	watching := len(watchlist.tokens)
+	watchlist.RUnlock()
+
+	return fmt.Sprintf("状态: %s\n上次扫描: %s (耗时 %s)\n发现代币: %d\n分析代币: %d\n本轮信号: %d\n累计信号: %d\n手动封禁: %d\n关注列表: %d",
+		state, formatTime(s.LastStart), s.LastEnd.Sub(s.LastStart).Round(time.Millisecond),
+		s.Discovered, s.Evaluated, s.Signals, s.TotalSignals, len(bannedAddresses()), watching)
+}
+
+func cmdBan(args []string) string {
+	if len(args) != 1 {
+		return "用法: /ban <合约地址>"
+	}
+	banAddress(args[0])
+	return "🚫 已封禁 " + args[0]
+}
+
+func cmdUnban(args []string) string {
+	if len(args) != 1 {
+		list := bannedAddresses()
+		if len(list) == 0 {
+			return "用法: /unban <合约地址>（当前无手动封禁）"
+		}
+		return "用法: /unban <合约地址>\n当前手动封禁:\n" + strings.Join(list, "\n")
+	}
+	if !unbanAddress(args[0]) {
+		return "未在手动封禁列表中: " + args[0]
+	}
+	return "✅ 已解封 " + args[0]
+}
+
+func cmdWatch(args []string) string {
+	if len(args) != 1 {
+		return "用法: /watch <合约地址>"
+	}
+	addr := args[0]
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	pool, symbol, err := geckoterminal.GetTopPool(ctx, "solana", addr, config.Proxy)
+	if err != nil {
+		return fmt.Sprintf("查询交易池失败: %v", err)
+	}
+	watchToken(types.TokenItem{
+		Chain:       "solana",
+		Address:     addr,
+		Symbol:      symbol,
+		PoolAddress: pool,
+		Emoje:       "👀",
+	})
+	return fmt.Sprintf("👀 已关注 %s\n池子: %s", symbol, pool)
+}
+
+func cmdUnwatch(args []string) string {
+	if len(args) != 1 {
+		return "用法: /unwatch <合约地址>"
+	}
+	if !unwatchToken(args[0]) {
+		return "未在关注列表中: " + args[0]
+	}
+	return "✅ 已取消关注 " + args[0]
+}
+
+// cmdDiag 输出该代币最近一次分析的各阶段指标
+func cmdDiag(args []string) string {
+	if len(args) != 1 {
+		return "用法: /diag <symbol>"
+	}
+	tokenDataMutex.Lock()
+	data, ok := tokenDataMap[args[0]]
+	if !ok {
+		// 符号大小写不敏感
+		for symbol, d := range tokenDataMap {
+			if strings.EqualFold(symbol, args[0]) {
+				data, ok = d, true
+				break
+			}
+		}
+	}
+	tokenDataMutex.Unlock()
+	if !ok {
+		return "未找到代币: " + args[0]
+	}
+
+	data.Mutex.Lock()
+	diag := data.Diag
+	shortDiag := data.ShortDiag
+	item := data.TokenItem
+	data.Mutex.Unlock()
+
+	if diag.Time.IsZero() {
+		return fmt.Sprintf("%s 尚未分析", item.Symbol)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s\n分析时间: %s\n", item.Symbol, item.Address, formatTime(diag.Time))
+	writeStages(&sb, diag)
+	if !shortDiag.Time.IsZero() {
+		sb.WriteString("空头级联:\n")
+		writeStages(&sb, shortDiag)
+	}
+	return sb.String()
+}
+
+// writeStages 每个阶段一行：结果、周期与指标值
+func writeStages(sb *strings.Builder, diag types.Diagnosis) {
+	for _, st := range diag.Stages {
+		mark := "❌"
+		if st.Passed {
+			mark = "✅"
+		}
+		keys := make([]string, 0, len(st.Values))
+		for k := range st.Values {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		fmt.Fprintf(sb, "%s %s", mark, st.Stage)
+		if st.Cached {
+			sb.WriteString("(缓存)")
+		}
+		for _, k := range keys {
+			fmt.Fprintf(sb, " %s=%.6g", k, st.Values[k])
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// redactedConfig 返回隐藏密钥后的配置副本（包括 webhook 地址，其本身即凭证）
+func redactedConfig() types.Config {
+	c := *config
+	c.Proxy = redactSecret(c.Proxy)
+	c.BotToken = redactSecret(c.BotToken)
+	c.Url = redactSecret(c.Url)
+	c.Filters = currentFilters()
+	c.Admin.Token = redactSecret(c.Admin.Token)
+	c.Admin.HMACSecret = redactSecret(c.Admin.HMACSecret)
+	c.Execution.Binance.APIKey = redactSecret(c.Execution.Binance.APIKey)
+	c.Execution.Binance.SecretKey = redactSecret(c.Execution.Binance.SecretKey)
+	c.Holders.RPCURL = redactSecret(c.Holders.RPCURL) // 付费节点的地址通常带 API key
+	c.Notifiers = make([]types.NotifierConfig, len(config.Notifiers))
+	for i, n := range config.Notifiers {
+		n.BotToken = redactSecret(n.BotToken)
+		n.Secret = redactSecret(n.Secret)
+		n.URL = redactSecret(n.URL)
+		c.Notifiers[i] = n
+	}
+	return c
+}
+
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 6 {
+		return "***"
+	}
+	return s[:4] + "***"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
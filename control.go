@@ -0,0 +1,173 @@
+package main
+
+import (
+	"onchain-energe-SRSI/stream"
+	"onchain-energe-SRSI/types"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	scannerPaused atomic.Bool // /pause 后 runScan 直接返回
+
+	// 手动封禁（按合约地址），与远程 banSymbols 分开保存，不会被定时刷新覆盖
+	manualBans = struct {
+		sync.RWMutex
+		addrs map[string]string // 小写地址 -> 原始地址
+	}{addrs: make(map[string]string)}
+
+	// 关注列表：即使不在 Axiom 榜单中也会每轮扫描
+	watchlist = struct {
+		sync.RWMutex
+		tokens map[string]types.TokenItem // key: 合约地址
+	}{tokens: make(map[string]types.TokenItem)}
+
+	// 运行时可修改的榜单过滤条件，初始值来自配置
+	activeFilters struct {
+		sync.RWMutex
+		cur types.FilterConfig
+	}
+
+	scanNowCh = make(chan struct{}, 1) // 管理接口触发立即扫描
+
+	scanStatus struct {
+		sync.Mutex
+		scanSummary
+	}
+)
+
+// scanSummary 最近一轮扫描的概况
+type scanSummary struct {
+	LastStart    time.Time `json:"last_start"`
+	LastEnd      time.Time `json:"last_end"`
+	Discovered   int       `json:"discovered"`    // 本轮榜单 + 关注列表
+	Evaluated    int       `json:"evaluated"`     // 本轮实际分析数量
+	Signals      int       `json:"signals"`       // 本轮触发信号数
+	TotalSignals int       `json:"total_signals"` // 启动以来累计信号
+}
+
+// isBanned 远程封禁按符号，手动封禁按地址
+func isBanned(token *types.TokenItem) bool {
+	if slices.Contains(banSymbols, token.Symbol) {
+		return true
+	}
+	manualBans.RLock()
+	defer manualBans.RUnlock()
+	_, banned := manualBans.addrs[strings.ToLower(token.Address)]
+	return banned
+}
+
+func banAddress(addr string) {
+	manualBans.Lock()
+	manualBans.addrs[strings.ToLower(addr)] = addr
+	manualBans.Unlock()
+	events.Publish(stream.EventBanList, "", banListChange{Source: "manual", Added: []string{addr}})
+}
+
+// unbanAddress 返回该地址之前是否处于手动封禁
+func unbanAddress(addr string) bool {
+	manualBans.Lock()
+	defer manualBans.Unlock()
+	key := strings.ToLower(addr)
+	_, existed := manualBans.addrs[key]
+	delete(manualBans.addrs, key)
+	if existed {
+		events.Publish(stream.EventBanList, "", banListChange{Source: "manual", Removed: []string{addr}})
+	}
+	return existed
+}
+
+// banListChange 封禁列表变化事件：remote 为按符号的远程列表，manual 为按地址的手动封禁
+type banListChange struct {
+	Source  string   `json:"source"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// publishBanListChange 远程列表有增删时推送
+func publishBanListChange(old, latest []string) {
+	change := banListChange{Source: "remote"}
+	for _, s := range latest {
+		if !slices.Contains(old, s) {
+			change.Added = append(change.Added, s)
+		}
+	}
+	for _, s := range old {
+		if !slices.Contains(latest, s) {
+			change.Removed = append(change.Removed, s)
+		}
+	}
+	if len(change.Added) > 0 || len(change.Removed) > 0 {
+		events.Publish(stream.EventBanList, "", change)
+	}
+}
+
+func bannedAddresses() []string {
+	manualBans.RLock()
+	defer manualBans.RUnlock()
+	res := make([]string, 0, len(manualBans.addrs))
+	for _, a := range manualBans.addrs {
+		res = append(res, a)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func watchToken(item types.TokenItem) {
+	watchlist.Lock()
+	watchlist.tokens[strings.ToLower(item.Address)] = item
+	watchlist.Unlock()
+}
+
+// unwatchToken 返回该地址之前是否在关注列表
+func unwatchToken(addr string) bool {
+	watchlist.Lock()
+	defer watchlist.Unlock()
+	key := strings.ToLower(addr)
+	_, existed := watchlist.tokens[key]
+	delete(watchlist.tokens, key)
+	return existed
+}
+
+// mergeWatchlist 把关注列表中不在榜单里的代币追加到本轮扫描
+func mergeWatchlist(tokenList []*types.TokenItem) []*types.TokenItem {
+	watchlist.RLock()
+	defer watchlist.RUnlock()
+
+	seen := make(map[string]bool, len(tokenList))
+	for _, t := range tokenList {
+		seen[strings.ToLower(t.Address)] = true
+	}
+	for addr, item := range watchlist.tokens {
+		if !seen[addr] {
+			item := item
+			tokenList = append(tokenList, &item)
+		}
+	}
+	return tokenList
+}
+
+// chainOfSymbol 首次警报只带符号，从已扫描的代币中查找所属链
+func chainOfSymbol(symbol string) string {
+	tokenDataMutex.Lock()
+	defer tokenDataMutex.Unlock()
+	if d, ok := tokenDataMap[symbol]; ok {
+		return d.TokenItem.Chain
+	}
+	return ""
+}
+
+func currentFilters() types.FilterConfig {
+	activeFilters.RLock()
+	defer activeFilters.RUnlock()
+	return activeFilters.cur
+}
+
+func setFilters(f types.FilterConfig) {
+	activeFilters.Lock()
+	activeFilters.cur = f
+	activeFilters.Unlock()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"onchain-energe-SRSI/holders"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/types"
+	"time"
+)
+
+// holderEnricher 持币与聪明钱补充，未启用时为 nil
+var holderEnricher *holders.Enricher
+
+// startHolders 按配置顺序创建数据来源
+func startHolders() error {
+	hc := config.Holders
+	if !hc.Enabled {
+		return nil
+	}
+	var providers []holders.Provider
+	for _, name := range hc.Providers {
+		switch name {
+		case "fixture":
+			f, err := holders.LoadFixture(hc.FixtureFile)
+			if err != nil {
+				return err
+			}
+			providers = append(providers, f)
+		case "solana_rpc":
+			r, err := holders.NewSolanaRPC(hc.RPCURL, config.Proxy)
+			if err != nil {
+				return err
+			}
+			providers = append(providers, r)
+		case "axiom":
+			providers = append(providers, holders.Axiom{})
+		default:
+			return fmt.Errorf("未知的持币数据来源: %s", name)
+		}
+	}
+	holderEnricher = holders.New(time.Duration(hc.GrowthWindowMin)*time.Minute, providers...)
+	return nil
+}
+
+// enrichHolders 分析前补齐持币字段并记录增长；请求期间不持有 data 的锁
+func enrichHolders(ctx context.Context, data *types.TokenData) {
+	if holderEnricher == nil {
+		return
+	}
+	data.Mutex.Lock()
+	item := data.TokenItem
+	data.Mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	growth, err := holderEnricher.Enrich(ctx, &item, time.Now())
+	if err != nil {
+		logging.From(ctx).Warn("获取持币数据失败", logging.KeyStage, "holders", "err", err)
+	}
+
+	data.Mutex.Lock()
+	data.TokenItem = item
+	data.Holders = growth
+	data.Mutex.Unlock()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"onchain-energe-SRSI/executor"
+	"onchain-energe-SRSI/types"
+	"time"
+)
+
+// tradeExecutor 自动下单，未启用时为 nil
+var tradeExecutor *executor.Manager
+
+// executionCtx 下单与平仓使用的 ctx，不随退出信号取消：
+// 关闭时排空信号期间提交的订单仍需执行，等待 tradeExecutor.Wait 之后才由 stopExecution 取消
+var (
+	executionCtx  = context.Background()
+	stopExecution = func() {}
+)
+
+// startExecution 按配置选择交易渠道；未显式启用时不创建
+func startExecution() error {
+	ec := config.Execution
+	if !ec.Enabled {
+		return nil
+	}
+	var exec executor.Executor
+	switch ec.Venue {
+	case "dry_run":
+		exec = executor.DryRun{}
+	case "binance":
+		// 合约代码由永续合约检查填充，未启用时所有订单都会被跳过
+		if !config.Perp.Enabled {
+			return fmt.Errorf("binance 渠道需要启用 perp.enabled")
+		}
+		if ec.Binance.APIKey == "" || ec.Binance.SecretKey == "" {
+			return fmt.Errorf("binance 渠道需要 api_key 与 secret_key")
+		}
+		b, err := executor.NewBinance(ec.Binance.APIKey, ec.Binance.SecretKey, ec.Binance.BaseURL, config.Proxy)
+		if err != nil {
+			return err
+		}
+		exec = b
+	case "jupiter":
+		if ec.Jupiter.UserPublicKey == "" {
+			return fmt.Errorf("jupiter 渠道需要 user_public_key")
+		}
+		exec = &executor.Jupiter{
+			BaseURL:       ec.Jupiter.BaseURL,
+			UserPublicKey: ec.Jupiter.UserPublicKey,
+			SlippageBps:   ec.Jupiter.SlippageBps,
+		}
+	default:
+		return fmt.Errorf("未知的交易渠道: %s", ec.Venue)
+	}
+
+	m := executor.NewManager(executor.Config{
+		PositionSize: ec.PositionSize,
+		MaxPositions: ec.MaxPositions,
+		Cooldown:     time.Duration(ec.CooldownMin) * time.Minute,
+		MaxHold:      time.Duration(ec.MaxHoldMin) * time.Minute,
+	}, exec)
+	if ec.KillSwitch {
+		m.SetKilled(true)
+	}
+	tradeExecutor = m
+	executionCtx, stopExecution = context.WithCancel(context.Background())
+	go m.Run(executionCtx)
+	return nil
+}
+
+// submitOrder 把信号交给执行器，未启用时忽略
+func submitOrder(sig types.Signal) {
+	if tradeExecutor != nil {
+		tradeExecutor.Submit(executionCtx, sig)
+	}
+}
+
+// executionHandler GET /api/execution：渠道、总开关与当前持仓
+func executionHandler(w http.ResponseWriter, r *http.Request) {
+	if tradeExecutor == nil {
+		writeError(w, http.StatusNotFound, "execution_disabled", "自动下单未启用")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"venue":     tradeExecutor.Venue(),
+		"killed":    tradeExecutor.Killed(),
+		"positions": tradeExecutor.Positions(),
+	})
+}
+
+// adminKillSwitch POST /api/admin/kill-switch {"killed": true}：停止或恢复开仓
+func adminKillSwitch(_ context.Context, body []byte) (any, error) {
+	if tradeExecutor == nil {
+		return nil, &httpError{http.StatusNotFound, "execution_disabled", "自动下单未启用"}
+	}
+	var req struct {
+		Killed *bool `json:"killed"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Killed == nil {
+		return nil, badRequest(`需要 {"killed": true|false}`)
+	}
+	tradeExecutor.SetKilled(*req.Killed)
+	return map[string]bool{"killed": *req.Killed}, nil
+}
@@ -0,0 +1,123 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// Binance 币安 USDT 本位合约市价单
+type Binance struct {
+	c *futures.Client
+
+	mu        sync.Mutex
+	precision map[string]int // 合约 -> 数量精度
+}
+
+// NewBinance baseURL 为空时使用官方地址，可指向测试网或本地假服务
+func NewBinance(apiKey, secretKey, baseURL, proxyURL string) (*Binance, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("代理地址无效: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	c := futures.NewClient(apiKey, secretKey)
+	if baseURL != "" {
+		c.BaseURL = baseURL
+	}
+	c.HTTPClient = &http.Client{Timeout: 15 * time.Second, Transport: transport}
+	return &Binance{c: c}, nil
+}
+
+func (b *Binance) Name() string { return "binance" }
+
+func (b *Binance) Execute(ctx context.Context, o Order) (Fill, error) {
+	if o.PerpSymbol == "" {
+		return Fill{}, ErrUnsupported
+	}
+	prec, err := b.quantityPrecision(ctx, o.PerpSymbol)
+	if err != nil {
+		return Fill{}, err
+	}
+	qty := o.Quantity
+	if !o.ReduceOnly {
+		res, err := b.c.NewPremiumIndexService().Symbol(o.PerpSymbol).Do(ctx)
+		if err != nil {
+			return Fill{}, fmt.Errorf("获取标记价格失败: %w", err)
+		}
+		if len(res) == 0 {
+			return Fill{}, fmt.Errorf("无标记价格: %s", o.PerpSymbol)
+		}
+		mark, err := strconv.ParseFloat(res[0].MarkPrice, 64)
+		if err != nil || mark <= 0 {
+			return Fill{}, fmt.Errorf("标记价格无效: %s", res[0].MarkPrice)
+		}
+		qty = o.Notional / mark
+	}
+	// 向下取整到合约精度
+	scale := math.Pow10(prec)
+	qty = math.Floor(qty*scale) / scale
+	if qty <= 0 {
+		return Fill{}, fmt.Errorf("下单数量过小: %s", o.PerpSymbol)
+	}
+
+	side := futures.SideTypeBuy
+	if o.Side == SideSell {
+		side = futures.SideTypeSell
+	}
+	svc := b.c.NewCreateOrderService().
+		Symbol(o.PerpSymbol).
+		Side(side).
+		Type(futures.OrderTypeMarket).
+		Quantity(strconv.FormatFloat(qty, 'f', prec, 64)).
+		NewOrderResponseType(futures.NewOrderRespTypeRESULT)
+	if o.ReduceOnly {
+		svc = svc.ReduceOnly(true)
+	}
+	resp, err := svc.Do(ctx)
+	if err != nil {
+		return Fill{}, fmt.Errorf("下单失败: %w", err)
+	}
+	filled, _ := strconv.ParseFloat(resp.ExecutedQuantity, 64)
+	price, _ := strconv.ParseFloat(resp.AvgPrice, 64)
+	return Fill{
+		Venue:    b.Name(),
+		OrderID:  strconv.FormatInt(resp.OrderID, 10),
+		Side:     o.Side,
+		Quantity: filled,
+		Price:    price,
+		Time:     time.Now(),
+	}, nil
+}
+
+// quantityPrecision 首次使用时读取交易规则并缓存
+func (b *Binance) quantityPrecision(ctx context.Context, symbol string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if p, ok := b.precision[symbol]; ok {
+		return p, nil
+	}
+	info, err := b.c.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+	b.precision = make(map[string]int, len(info.Symbols))
+	for _, s := range info.Symbols {
+		b.precision[s.Symbol] = s.QuantityPrecision
+	}
+	p, ok := b.precision[symbol]
+	if !ok {
+		return 0, ErrUnsupported
+	}
+	return p, nil
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// fakeFutures 本地假币安合约接口，记录下单参数
+type fakeFutures struct {
+	*httptest.Server
+	mu     sync.Mutex
+	orders []url.Values
+	infos  int
+}
+
+func newFakeFutures(t *testing.T) *fakeFutures {
+	f := &fakeFutures{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch r.URL.Path {
+		case "/fapi/v1/exchangeInfo":
+			f.infos++
+			io.WriteString(w, `{"symbols":[{"symbol":"WIFUSDT","quantityPrecision":1}]}`)
+		case "/fapi/v1/premiumIndex":
+			io.WriteString(w, `{"symbol":"WIFUSDT","markPrice":"2.5","lastFundingRate":"0.0001"}`)
+		case "/fapi/v1/order":
+			if r.Method != http.MethodPost {
+				t.Errorf("下单方法 = %s", r.Method)
+			}
+			if r.Header.Get("X-MBX-APIKEY") != "key" {
+				t.Errorf("缺少 API Key")
+			}
+			r.ParseForm()
+			if r.Form.Get("signature") == "" {
+				t.Errorf("下单请求未签名")
+			}
+			f.orders = append(f.orders, r.Form)
+			io.WriteString(w, `{"orderId":123,"symbol":"WIFUSDT","executedQty":"3.9","avgPrice":"2.51","status":"FILLED"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"code":-1,"msg":"not found"}`)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestBinanceOpenAndClose(t *testing.T) {
+	f := newFakeFutures(t)
+	b, err := NewBinance("key", "secret", f.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 开仓：10 USDT / 标记价 2.5 = 4，按精度 1 取整
+	fill, err := b.Execute(context.Background(), Order{PerpSymbol: "WIFUSDT", Side: SideBuy, Notional: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fill.OrderID != "123" || fill.Quantity != 3.9 || fill.Price != 2.51 || fill.DryRun {
+		t.Errorf("fill = %+v", fill)
+	}
+	// 平仓：按持仓数量反向下单并只减仓
+	if _, err := b.Execute(context.Background(), Order{PerpSymbol: "WIFUSDT", Side: SideSell, ReduceOnly: true, Quantity: 3.96}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.orders) != 2 {
+		t.Fatalf("下单 %d 次, want 2", len(f.orders))
+	}
+	open, closing := f.orders[0], f.orders[1]
+	for k, want := range map[string]string{"symbol": "WIFUSDT", "side": "BUY", "type": "MARKET", "quantity": "4.0"} {
+		if open.Get(k) != want {
+			t.Errorf("开仓 %s = %q, want %q", k, open.Get(k), want)
+		}
+	}
+	if open.Get("reduceOnly") == "true" {
+		t.Error("开仓不应只减仓")
+	}
+	for k, want := range map[string]string{"side": "SELL", "quantity": "3.9", "reduceOnly": "true"} {
+		if closing.Get(k) != want {
+			t.Errorf("平仓 %s = %q, want %q", k, closing.Get(k), want)
+		}
+	}
+	if f.infos != 1 {
+		t.Errorf("交易规则应只读取一次, got %d", f.infos)
+	}
+}
+
+func TestBinanceUnsupported(t *testing.T) {
+	f := newFakeFutures(t)
+	b, err := NewBinance("key", "secret", f.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range []Order{
+		{Side: SideBuy, Notional: 10},                         // 未上线永续
+		{PerpSymbol: "NOPEUSDT", Side: SideBuy, Notional: 10}, // 交易规则中没有
+	} {
+		if _, err := b.Execute(context.Background(), o); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Execute(%+v) err = %v, want ErrUnsupported", o, err)
+		}
+	}
+	if _, err := b.Execute(context.Background(), Order{PerpSymbol: "WIFUSDT", Side: SideBuy, Notional: 0.01}); err == nil {
+		t.Error("数量取整为 0 时应返回错误")
+	}
+	if len(f.orders) != 0 {
+		t.Errorf("不应下单, got %d", len(f.orders))
+	}
+}
@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"onchain-energe-SRSI/logging"
+	"time"
+)
+
+// DryRun 只记录日志，按信号价成交
+type DryRun struct{}
+
+func (DryRun) Name() string { return "dry_run" }
+
+func (DryRun) Execute(ctx context.Context, o Order) (Fill, error) {
+	if o.Price <= 0 {
+		return Fill{}, fmt.Errorf("价格无效: %v", o.Price)
+	}
+	qty := o.Quantity
+	if !o.ReduceOnly {
+		qty = o.Notional / o.Price
+	}
+	logging.From(ctx).Info("模拟下单", "side", o.Side, "reduce_only", o.ReduceOnly,
+		logging.KeySymbol, o.Symbol, logging.KeyAddress, o.Address, "quantity", qty, "price", o.Price)
+	return Fill{
+		Venue:    "dry_run",
+		OrderID:  fmt.Sprintf("dry-%d", time.Now().UnixNano()),
+		Side:     o.Side,
+		Quantity: qty,
+		Price:    o.Price,
+		Time:     time.Now(),
+		DryRun:   true,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 下单方向
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// ErrUnsupported 该渠道无法交易此代币或方向（如未上线合约、现货做空）
+var ErrUnsupported = errors.New("渠道不支持该代币或方向")
+
+// Order 下单请求；开仓按 Notional 下单，平仓（ReduceOnly）按 Quantity 下单
+type Order struct {
+	SignalID   string
+	Chain      string
+	Symbol     string
+	Address    string
+	PerpSymbol string // 币安合约，未上线时为空
+	Side       string
+	ReduceOnly bool
+	Notional   float64 // 开仓名义金额：binance 为 USDT，jupiter / dry_run 为 SOL
+	Quantity   float64
+	Price      float64 // 信号价，dry_run 以此成交
+}
+
+// Fill 成交回报
+type Fill struct {
+	Venue    string    `json:"venue"`
+	OrderID  string    `json:"order_id"`
+	Side     string    `json:"side"`
+	Quantity float64   `json:"quantity"`
+	Price    float64   `json:"price"`
+	Time     time.Time `json:"time"`
+	DryRun   bool      `json:"dry_run"` // 未真实成交
+}
+
+// Executor 交易渠道
+type Executor interface {
+	Name() string
+	Execute(ctx context.Context, o Order) (Fill, error)
+}
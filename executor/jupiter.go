@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SOLMint 包装 SOL 的 mint 地址
+const SOLMint = "So11111111111111111111111111111111111111112"
+
+// Jupiter Solana 现货兑换（Jupiter 风格 quote / swap 接口）。
+// 目前只获取报价并生成未签名交易，不签名、不广播，成交回报标记为 DryRun；数量为代币最小单位
+type Jupiter struct {
+	BaseURL       string // 如 https://quote-api.jup.ag/v6
+	UserPublicKey string
+	SlippageBps   int
+	Client        *http.Client
+}
+
+func (j *Jupiter) Name() string { return "jupiter" }
+
+type jupiterQuote struct {
+	InAmount  string `json:"inAmount"`
+	OutAmount string `json:"outAmount"`
+}
+
+func (j *Jupiter) Execute(ctx context.Context, o Order) (Fill, error) {
+	if o.Chain != "" && o.Chain != "solana" {
+		return Fill{}, ErrUnsupported
+	}
+	// 现货只能买入后卖出
+	var input, output string
+	var amount uint64
+	switch {
+	case o.Side == SideBuy && !o.ReduceOnly:
+		input, output, amount = SOLMint, o.Address, uint64(o.Notional*1e9)
+	case o.Side == SideSell && o.ReduceOnly:
+		input, output, amount = o.Address, SOLMint, uint64(o.Quantity)
+	default:
+		return Fill{}, ErrUnsupported
+	}
+	if amount == 0 {
+		return Fill{}, fmt.Errorf("下单数量过小: %s", o.Symbol)
+	}
+
+	q := url.Values{}
+	q.Set("inputMint", input)
+	q.Set("outputMint", output)
+	q.Set("amount", strconv.FormatUint(amount, 10))
+	q.Set("slippageBps", strconv.Itoa(j.SlippageBps))
+	var raw json.RawMessage
+	if err := j.do(ctx, http.MethodGet, "/quote?"+q.Encode(), nil, &raw); err != nil {
+		return Fill{}, fmt.Errorf("获取报价失败: %w", err)
+	}
+	var quote jupiterQuote
+	if err := json.Unmarshal(raw, &quote); err != nil {
+		return Fill{}, fmt.Errorf("解析报价失败: %w", err)
+	}
+	in, err1 := strconv.ParseFloat(quote.InAmount, 64)
+	out, err2 := strconv.ParseFloat(quote.OutAmount, 64)
+	if err1 != nil || err2 != nil || in <= 0 || out <= 0 {
+		return Fill{}, fmt.Errorf("报价数量无效: in=%s out=%s", quote.InAmount, quote.OutAmount)
+	}
+
+	var swap struct {
+		SwapTransaction string `json:"swapTransaction"`
+	}
+	body, _ := json.Marshal(map[string]any{
+		"quoteResponse":    raw,
+		"userPublicKey":    j.UserPublicKey,
+		"wrapAndUnwrapSol": true,
+	})
+	if err := j.do(ctx, http.MethodPost, "/swap", body, &swap); err != nil {
+		return Fill{}, fmt.Errorf("生成兑换交易失败: %w", err)
+	}
+	if swap.SwapTransaction == "" {
+		return Fill{}, fmt.Errorf("兑换交易为空")
+	}
+
+	// 价格以 SOL / 代币最小单位计
+	fill := Fill{Venue: j.Name(), Side: o.Side, Time: time.Now(), DryRun: true}
+	if o.ReduceOnly {
+		fill.Quantity, fill.Price = in, out/1e9/in
+	} else {
+		fill.Quantity, fill.Price = out, in/1e9/out
+	}
+	return fill, nil
+}
+
+func (j *Jupiter) do(ctx context.Context, method, path string, body []byte, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, j.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := j.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("状态码 %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// fakeJupiter 本地假 Jupiter 接口，按 amount 报出固定的 outAmount
+func fakeJupiter(t *testing.T, out string, quotes *[]url.Values, swaps *[]map[string]any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/quote":
+			q := r.URL.Query()
+			*quotes = append(*quotes, q)
+			json.NewEncoder(w).Encode(map[string]string{"inAmount": q.Get("amount"), "outAmount": out})
+		case r.Method == http.MethodPost && r.URL.Path == "/swap":
+			if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("swap Content-Type = %q", ct)
+			}
+			var body map[string]any
+			b, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(b, &body); err != nil {
+				t.Errorf("swap 请求体无效: %v", err)
+			}
+			*swaps = append(*swaps, body)
+			io.WriteString(w, `{"swapTransaction":"AQID"}`)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestJupiterExecute(t *testing.T) {
+	const token = "TokenMint111"
+	tests := []struct {
+		name       string
+		order      Order
+		out        string
+		wantIn     string
+		wantOut    string
+		wantAmount string
+		wantQty    float64
+		wantPrice  float64
+	}{
+		{
+			name:  "买入：SOL 换代币",
+			order: Order{Chain: "solana", Address: token, Side: SideBuy, Notional: 0.5},
+			out:   "250000", wantIn: SOLMint, wantOut: token, wantAmount: "500000000",
+			wantQty: 250000, wantPrice: 0.5 / 250000,
+		},
+		{
+			name:  "平仓：代币换回 SOL",
+			order: Order{Address: token, Side: SideSell, ReduceOnly: true, Quantity: 250000},
+			out:   "600000000", wantIn: token, wantOut: SOLMint, wantAmount: "250000",
+			wantQty: 250000, wantPrice: 0.6 / 250000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var quotes []url.Values
+			var swaps []map[string]any
+			srv := fakeJupiter(t, tt.out, &quotes, &swaps)
+			j := &Jupiter{BaseURL: srv.URL, UserPublicKey: "Wallet111", SlippageBps: 150}
+
+			fill, err := j.Execute(context.Background(), tt.order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(quotes) != 1 || len(swaps) != 1 {
+				t.Fatalf("quote %d 次, swap %d 次", len(quotes), len(swaps))
+			}
+			q := quotes[0]
+			for k, want := range map[string]string{"inputMint": tt.wantIn, "outputMint": tt.wantOut, "amount": tt.wantAmount, "slippageBps": "150"} {
+				if q.Get(k) != want {
+					t.Errorf("quote %s = %q, want %q", k, q.Get(k), want)
+				}
+			}
+			s := swaps[0]
+			if s["userPublicKey"] != "Wallet111" || s["wrapAndUnwrapSol"] != true {
+				t.Errorf("swap body = %v", s)
+			}
+			if qr, _ := s["quoteResponse"].(map[string]any); qr["outAmount"] != tt.out {
+				t.Errorf("swap 未原样带上报价: %v", s["quoteResponse"])
+			}
+			if !fill.DryRun || fill.Venue != "jupiter" || fill.Side != tt.order.Side {
+				t.Errorf("fill = %+v", fill)
+			}
+			if fill.Quantity != tt.wantQty || fill.Price != tt.wantPrice {
+				t.Errorf("成交 %v @ %v, want %v @ %v", fill.Quantity, fill.Price, tt.wantQty, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestJupiterUnsupported(t *testing.T) {
+	var quotes []url.Values
+	var swaps []map[string]any
+	srv := fakeJupiter(t, "1", &quotes, &swaps)
+	j := &Jupiter{BaseURL: srv.URL, UserPublicKey: "Wallet111"}
+	for _, o := range []Order{
+		{Chain: "bsc", Address: "x", Side: SideBuy, Notional: 1}, // 非 Solana
+		{Address: "x", Side: SideSell, Notional: 1},              // 现货不能开空
+		{Address: "x", Side: SideBuy, ReduceOnly: true},          // 买入不能平仓
+	} {
+		if _, err := j.Execute(context.Background(), o); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Execute(%+v) err = %v, want ErrUnsupported", o, err)
+		}
+	}
+	if len(quotes) != 0 {
+		t.Errorf("不应请求报价, got %d", len(quotes))
+	}
+}
+
+func TestJupiterQuoteError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"no route"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	j := &Jupiter{BaseURL: srv.URL, UserPublicKey: "Wallet111"}
+	if _, err := j.Execute(context.Background(), Order{Address: "x", Side: SideBuy, Notional: 1}); err == nil {
+		t.Error("报价失败时应返回错误")
+	}
+}
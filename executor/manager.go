@@ -0,0 +1,260 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/types"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config 风控参数
+type Config struct {
+	PositionSize float64       // 每笔开仓名义金额
+	MaxPositions int           // 同时持仓上限
+	Cooldown     time.Duration // 同一代币两次开仓的最小间隔
+	MaxHold      time.Duration // 超过后自动平仓，空单没有离场信号时依赖它
+}
+
+// Position 由执行器开出的持仓
+type Position struct {
+	SignalID   string    `json:"signal_id"`
+	Chain      string    `json:"chain"`
+	Symbol     string    `json:"symbol"`
+	Address    string    `json:"address"`
+	PerpSymbol string    `json:"perp_symbol,omitempty"`
+	Side       string    `json:"side"` // 开仓方向
+	Entry      Fill      `json:"entry"`
+	OpenedAt   time.Time `json:"opened_at"`
+}
+
+// Manager 把信号转为订单：仓位大小、持仓上限、代币冷却与总开关在这里统一处理
+type Manager struct {
+	cfg  Config
+	exec Executor
+
+	killed atomic.Bool
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	positions map[string]*Position // 地址 -> 持仓
+	pending   map[string]bool      // 下单中的地址，占用持仓名额
+	lastOpen  map[string]time.Time
+}
+
+func NewManager(cfg Config, exec Executor) *Manager {
+	return &Manager{
+		cfg:       cfg,
+		exec:      exec,
+		positions: make(map[string]*Position),
+		pending:   make(map[string]bool),
+		lastOpen:  make(map[string]time.Time),
+	}
+}
+
+// Venue 交易渠道名
+func (m *Manager) Venue() string { return m.exec.Name() }
+
+// SetKilled 打开总开关后不再开仓，平仓不受影响
+func (m *Manager) SetKilled(killed bool) {
+	m.killed.Store(killed)
+	logging.Component("executor").Warn("交易总开关", "killed", killed)
+}
+
+func (m *Manager) Killed() bool { return m.killed.Load() }
+
+// Submit 异步处理信号：新信号开仓，带 ReplyTo 的离场信号平掉该代币持仓
+func (m *Manager) Submit(ctx context.Context, sig types.Signal) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		ctx = logging.With(ctx, logging.KeyComponent, "executor", "venue", m.exec.Name(),
+			logging.KeySymbol, sig.Symbol, logging.KeyAddress, sig.Address)
+		if sig.ReplyTo != "" {
+			m.closePosition(ctx, sig.Address, "exit_signal")
+			return
+		}
+		m.open(ctx, sig)
+	}()
+}
+
+func (m *Manager) open(ctx context.Context, sig types.Signal) {
+	logger := logging.From(ctx)
+	if m.Killed() {
+		logger.Info("总开关已打开，跳过开仓", "signal_id", sig.ID)
+		return
+	}
+
+	now := time.Now()
+	m.mu.Lock()
+	reason := ""
+	switch {
+	case m.positions[sig.Address] != nil || m.pending[sig.Address]:
+		reason = "已持仓"
+	case now.Sub(m.lastOpen[sig.Address]) < m.cfg.Cooldown:
+		reason = "冷却中"
+	case len(m.positions)+len(m.pending) >= m.cfg.MaxPositions:
+		reason = "持仓已满"
+	}
+	if reason != "" {
+		m.mu.Unlock()
+		logger.Info("跳过开仓", "reason", reason, "signal_id", sig.ID)
+		return
+	}
+	m.pending[sig.Address] = true
+	m.mu.Unlock()
+
+	side := SideBuy
+	if sig.Short {
+		side = SideSell
+	}
+	o := Order{
+		SignalID: sig.ID,
+		Chain:    sig.Chain,
+		Symbol:   sig.Symbol,
+		Address:  sig.Address,
+		Side:     side,
+		Notional: m.cfg.PositionSize,
+		Price:    sig.Price,
+	}
+	if sig.Perp != nil {
+		o.PerpSymbol = sig.Perp.Symbol
+	}
+	fill, err := m.exec.Execute(ctx, o)
+
+	m.mu.Lock()
+	delete(m.pending, sig.Address)
+	// 只有成交后才进入冷却，失败或渠道不支持时下一次信号可以重试
+	if err == nil {
+		m.lastOpen[sig.Address] = now
+		m.positions[sig.Address] = &Position{
+			SignalID:   sig.ID,
+			Chain:      sig.Chain,
+			Symbol:     sig.Symbol,
+			Address:    sig.Address,
+			PerpSymbol: o.PerpSymbol,
+			Side:       side,
+			Entry:      fill,
+			OpenedAt:   fill.Time,
+		}
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			logger.Info("渠道不支持，跳过开仓", "signal_id", sig.ID)
+			metrics.Orders.Inc(m.exec.Name(), side, "unsupported")
+			return
+		}
+		logger.Error("开仓失败", "signal_id", sig.ID, "err", err)
+		metrics.Orders.Inc(m.exec.Name(), side, "error")
+		return
+	}
+	metrics.Orders.Inc(m.exec.Name(), side, "filled")
+	logger.Info("已开仓", "signal_id", sig.ID, "side", side, "order_id", fill.OrderID,
+		"quantity", fill.Quantity, "price", fill.Price, "dry_run", fill.DryRun)
+}
+
+func (m *Manager) closePosition(ctx context.Context, address, reason string) {
+	logger := logging.From(ctx)
+	m.mu.Lock()
+	pos := m.positions[address]
+	if pos == nil {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.positions, address)
+	m.mu.Unlock()
+
+	side := SideSell
+	if pos.Side == SideSell {
+		side = SideBuy
+	}
+	fill, err := m.exec.Execute(ctx, Order{
+		SignalID:   pos.SignalID,
+		Chain:      pos.Chain,
+		Symbol:     pos.Symbol,
+		Address:    pos.Address,
+		PerpSymbol: pos.PerpSymbol,
+		Side:       side,
+		ReduceOnly: true,
+		Quantity:   pos.Entry.Quantity,
+		Price:      pos.Entry.Price,
+	})
+	if err != nil {
+		// 平仓失败时保留持仓，下一次离场信号或到期检查重试
+		m.mu.Lock()
+		if m.positions[address] == nil {
+			m.positions[address] = pos
+		}
+		m.mu.Unlock()
+		logger.Error("平仓失败", "reason", reason, "signal_id", pos.SignalID, "err", err)
+		metrics.Orders.Inc(m.exec.Name(), side, "error")
+		return
+	}
+	metrics.Orders.Inc(m.exec.Name(), side, "filled")
+	logger.Info("已平仓", "reason", reason, "signal_id", pos.SignalID, "order_id", fill.OrderID,
+		"quantity", fill.Quantity, "price", fill.Price, "dry_run", fill.DryRun)
+}
+
+// Run 每分钟平掉超过最长持有时间的持仓，直到 ctx 取消
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.closeExpired(ctx, now)
+		}
+	}
+}
+
+// closeExpired 平掉 now 时已超过最长持有时间的持仓
+func (m *Manager) closeExpired(ctx context.Context, now time.Time) {
+	m.mu.Lock()
+	var expired []*Position
+	for _, p := range m.positions {
+		if now.Sub(p.OpenedAt) >= m.cfg.MaxHold {
+			expired = append(expired, p)
+		}
+	}
+	m.mu.Unlock()
+	for _, p := range expired {
+		pctx := logging.With(ctx, logging.KeyComponent, "executor", "venue", m.exec.Name(),
+			logging.KeySymbol, p.Symbol, logging.KeyAddress, p.Address)
+		m.closePosition(pctx, p.Address, "max_hold")
+	}
+}
+
+// Positions 当前持仓
+func (m *Manager) Positions() []Position {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make([]Position, 0, len(m.positions))
+	for _, p := range m.positions {
+		res = append(res, *p)
+	}
+	return res
+}
+
+// Wait 等待进行中的下单完成或 ctx 到期
+func (m *Manager) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
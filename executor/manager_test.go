@@ -0,0 +1,209 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"onchain-energe-SRSI/types"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubExec 记录订单，按顺序返回 errs 中的错误，release 非 nil 时阻塞到其关闭
+type stubExec struct {
+	mu      sync.Mutex
+	orders  []Order
+	errs    []error
+	release chan struct{}
+	started chan struct{}
+	at      time.Time
+}
+
+func (*stubExec) Name() string { return "stub" }
+
+func (s *stubExec) Execute(ctx context.Context, o Order) (Fill, error) {
+	if s.started != nil {
+		s.started <- struct{}{}
+	}
+	if s.release != nil {
+		<-s.release
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders = append(s.orders, o)
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		if err != nil {
+			return Fill{}, err
+		}
+	}
+	at := s.at
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return Fill{Venue: "stub", Side: o.Side, Quantity: 7, Price: o.Price, Time: at}, nil
+}
+
+func (s *stubExec) sent() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Order(nil), s.orders...)
+}
+
+func newTestManager(exec Executor) *Manager {
+	return NewManager(Config{PositionSize: 10, MaxPositions: 2, Cooldown: time.Hour, MaxHold: time.Hour}, exec)
+}
+
+// submit 提交并等待处理完
+func submit(t *testing.T, m *Manager, sig types.Signal) {
+	t.Helper()
+	m.Submit(context.Background(), sig)
+	if err := m.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func buy(addr string) types.Signal {
+	return types.Signal{ID: "sig-" + addr, Chain: "solana", Symbol: addr, Address: addr, Price: 2, Perp: &types.PerpListing{Symbol: addr + "USDT"}}
+}
+
+func exit(addr string) types.Signal {
+	return types.Signal{ID: "exit-" + addr, ReplyTo: "sig-" + addr, Address: addr, Symbol: addr}
+}
+
+func TestManagerOpenCloseAndCooldown(t *testing.T) {
+	s := &stubExec{}
+	m := newTestManager(s)
+	submit(t, m, buy("A"))
+	submit(t, m, buy("A")) // 已持仓
+	if n := len(s.sent()); n != 1 {
+		t.Fatalf("下单 %d 次, want 1", n)
+	}
+	o := s.sent()[0]
+	if o.Side != SideBuy || o.ReduceOnly || o.Notional != 10 || o.PerpSymbol != "AUSDT" || o.Price != 2 {
+		t.Errorf("开仓订单 = %+v", o)
+	}
+
+	submit(t, m, exit("A"))
+	submit(t, m, exit("A")) // 已无持仓，不再下单
+	orders := s.sent()
+	if len(orders) != 2 {
+		t.Fatalf("下单 %d 次, want 2", len(orders))
+	}
+	c := orders[1]
+	if c.Side != SideSell || !c.ReduceOnly || c.Quantity != 7 || c.PerpSymbol != "AUSDT" {
+		t.Errorf("平仓订单 = %+v", c)
+	}
+	if len(m.Positions()) != 0 {
+		t.Error("平仓后不应有持仓")
+	}
+
+	submit(t, m, buy("A")) // 冷却中
+	if len(s.sent()) != 2 {
+		t.Error("冷却期内不应再次开仓")
+	}
+}
+
+func TestManagerFailedOpenDoesNotCoolDown(t *testing.T) {
+	for _, err := range []error{errors.New("timeout"), ErrUnsupported} {
+		t.Run(err.Error(), func(t *testing.T) {
+			s := &stubExec{errs: []error{err}}
+			m := newTestManager(s)
+			submit(t, m, buy("A"))
+			if len(m.Positions()) != 0 {
+				t.Fatal("失败时不应记录持仓")
+			}
+			submit(t, m, buy("A"))
+			if len(s.sent()) != 2 || len(m.Positions()) != 1 {
+				t.Errorf("失败后应可立即重试: 下单 %d 次, 持仓 %d", len(s.sent()), len(m.Positions()))
+			}
+		})
+	}
+}
+
+func TestManagerShort(t *testing.T) {
+	s := &stubExec{}
+	m := newTestManager(s)
+	sig := buy("A")
+	sig.Short = true
+	submit(t, m, sig)
+	submit(t, m, exit("A"))
+	orders := s.sent()
+	if len(orders) != 2 || orders[0].Side != SideSell || orders[1].Side != SideBuy || !orders[1].ReduceOnly {
+		t.Errorf("空单开平方向错误: %+v", orders)
+	}
+}
+
+func TestManagerKillSwitch(t *testing.T) {
+	s := &stubExec{}
+	m := newTestManager(s)
+	submit(t, m, buy("A"))
+	m.SetKilled(true)
+	submit(t, m, buy("B"))
+	if len(s.sent()) != 1 {
+		t.Error("总开关打开后不应开仓")
+	}
+	submit(t, m, exit("A"))
+	if orders := s.sent(); len(orders) != 2 || !orders[1].ReduceOnly {
+		t.Error("总开关不影响平仓")
+	}
+	m.SetKilled(false)
+	submit(t, m, buy("B"))
+	if len(s.sent()) != 3 {
+		t.Error("关闭总开关后应恢复开仓")
+	}
+}
+
+func TestManagerMaxPositionsCountsPending(t *testing.T) {
+	s := &stubExec{release: make(chan struct{}), started: make(chan struct{}, 4)}
+	m := newTestManager(s)
+	m.Submit(context.Background(), buy("A"))
+	<-s.started // A 下单中，占用一个名额
+	m.Submit(context.Background(), buy("A"))
+	m.Submit(context.Background(), buy("B"))
+	<-s.started
+	m.Submit(context.Background(), buy("C")) // A 下单中 + B 下单中 = 上限
+	time.Sleep(20 * time.Millisecond)
+	close(s.release)
+	if err := m.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(s.sent()); n != 2 {
+		t.Errorf("下单 %d 次, want 2（重复的 A 与超出上限的 C 应跳过）", n)
+	}
+	if n := len(m.Positions()); n != 2 {
+		t.Errorf("持仓 %d, want 2", n)
+	}
+}
+
+func TestManagerCloseFailureKeepsPosition(t *testing.T) {
+	s := &stubExec{errs: []error{nil, errors.New("rejected")}}
+	m := newTestManager(s)
+	submit(t, m, buy("A"))
+	submit(t, m, exit("A"))
+	if len(m.Positions()) != 1 {
+		t.Fatal("平仓失败时应保留持仓")
+	}
+	submit(t, m, exit("A"))
+	if len(m.Positions()) != 0 || len(s.sent()) != 3 {
+		t.Error("下一次离场信号应重试平仓")
+	}
+}
+
+func TestManagerCloseExpired(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &stubExec{at: t0}
+	m := newTestManager(s)
+	submit(t, m, buy("A"))
+
+	m.closeExpired(context.Background(), t0.Add(59*time.Minute))
+	if len(m.Positions()) != 1 {
+		t.Fatal("未到最长持有时间不应平仓")
+	}
+	m.closeExpired(context.Background(), t0.Add(time.Hour))
+	orders := s.sent()
+	if len(m.Positions()) != 0 || len(orders) != 2 || !orders[1].ReduceOnly {
+		t.Errorf("到期应平仓: 持仓 %d, 订单 %+v", len(m.Positions()), orders)
+	}
+}
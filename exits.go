@@ -0,0 +1,86 @@
+package main
+
+import (
+	"onchain-energe-SRSI/types"
+	"sync"
+	"time"
+)
+
+// exitWatch 近期触发过买入信号的代币，按地址记录最新一次信号与最近一次 XSELL 时间
+var exitWatch = struct {
+	sync.Mutex
+	tokens map[string]*watchedToken
+}{tokens: make(map[string]*watchedToken)}
+
+type watchedToken struct {
+	signal   types.Signal
+	lastExit time.Time
+}
+
+// watchSignal 买入信号触发后开始离场评估；同一代币再次触发时改为引用新信号
+func watchSignal(sig types.Signal) {
+	sig.Chart = nil
+	exitWatch.Lock()
+	defer exitWatch.Unlock()
+	if w, ok := exitWatch.tokens[sig.Address]; ok {
+		w.signal = sig
+		return
+	}
+	exitWatch.tokens[sig.Address] = &watchedToken{signal: sig}
+}
+
+// exitCandidates 本轮需评估离场的代币：窗口内触发过的，以及模拟盘多头持仓；冷却中的跳过
+func exitCandidates(now time.Time) []types.Signal {
+	window := time.Duration(config.Exit.WindowMin) * time.Minute
+	cooldown := time.Duration(config.Exit.CooldownMin) * time.Minute
+
+	held := map[string]types.Signal{}
+	if paperEngine != nil {
+		for _, p := range paperEngine.Positions() {
+			if p.Short {
+				continue
+			}
+			held[p.Address] = types.Signal{
+				ID:          p.ID,
+				Strategy:    p.Strategy,
+				Chain:       p.Chain,
+				Symbol:      p.Symbol,
+				Address:     p.Address,
+				PoolAddress: p.PoolAddress,
+				Price:       p.SignalPrice,
+				Time:        p.OpenedAt,
+			}
+		}
+	}
+
+	exitWatch.Lock()
+	defer exitWatch.Unlock()
+	var res []types.Signal
+	for addr, w := range exitWatch.tokens {
+		_, isHeld := held[addr]
+		if !isHeld && now.Sub(w.signal.Time) > window {
+			delete(exitWatch.tokens, addr)
+			continue
+		}
+		delete(held, addr)
+		if now.Sub(w.lastExit) < cooldown {
+			continue
+		}
+		res = append(res, w.signal)
+	}
+	// 重启后未在窗口记录中的持仓
+	for addr, sig := range held {
+		exitWatch.tokens[addr] = &watchedToken{signal: sig}
+		res = append(res, sig)
+	}
+	return res
+}
+
+// markExit 记录 XSELL 时间，冷却期内不再评估该代币
+func markExit(address string, at time.Time) {
+	exitWatch.Lock()
+	defer exitWatch.Unlock()
+	if w, ok := exitWatch.tokens[address]; ok {
+		w.lastExit = at
+	}
+}
@@ -0,0 +1,63 @@
+package flow
+
+import (
+	"fmt"
+	"math"
+)
+
+// Stats 买卖力量与放量情况，NaN 表示数据不足
+type Stats struct {
+	Buys     int     `json:"buys"`
+	Sells    int     `json:"sells"`
+	BuyRatio float64 `json:"buy_ratio"` // 买单笔数占比 0~1
+	Volume   float64 `json:"volume"`    // 所取 K 线的成交量
+	Baseline float64 `json:"baseline"`  // 之前 window 根的平均成交量
+	Spike    float64 `json:"spike"`     // Volume / Baseline
+}
+
+// BuyRatio 买单占买卖总笔数的比例，没有成交时为 NaN
+func BuyRatio(buys, sells int) float64 {
+	if buys < 0 || sells < 0 || buys+sells == 0 {
+		return math.NaN()
+	}
+	return float64(buys) / float64(buys+sells)
+}
+
+// VolumeSpike 第 i 根的成交量相对之前 window 根平均值的倍数；基线不足 window 根或为 0 时 ratio 为 NaN
+func VolumeSpike(volumes []float64, i, window int) (volume, baseline, ratio float64) {
+	if i < 0 || i >= len(volumes) || window <= 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	volume = volumes[i]
+	if i < window {
+		return volume, math.NaN(), math.NaN()
+	}
+	sum := 0.0
+	for _, v := range volumes[i-window : i] {
+		sum += v
+	}
+	baseline = sum / float64(window)
+	if baseline <= 0 {
+		return volume, baseline, math.NaN()
+	}
+	return volume, baseline, volume / baseline
+}
+
+// Compute 汇总买卖笔数与第 i 根 K 线的放量
+func Compute(buys, sells int, volumes []float64, i, window int) Stats {
+	s := Stats{Buys: buys, Sells: sells, BuyRatio: BuyRatio(buys, sells)}
+	s.Volume, s.Baseline, s.Spike = VolumeSpike(volumes, i, window)
+	return s
+}
+
+// Format 告警中附带的一行，例如 "📊 量比 2.3x（20 根均量） | 买单 64%（64/100）"
+func Format(s Stats, window int) string {
+	line := "📊 量比 -"
+	if !math.IsNaN(s.Spike) {
+		line = fmt.Sprintf("📊 量比 %.1fx（%d 根均量）", s.Spike, window)
+	}
+	if !math.IsNaN(s.BuyRatio) {
+		line += fmt.Sprintf(" | 买单 %.0f%%（%d/%d）", s.BuyRatio*100, s.Buys, s.Buys+s.Sells)
+	}
+	return line
+}
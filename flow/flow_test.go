@@ -0,0 +1,60 @@
+package flow
+
+import (
+	"math"
+	"testing"
+)
+
+// same 比较浮点数，NaN 与 NaN 视为相等
+func same(a, b float64) bool {
+	return a == b || math.IsNaN(a) && math.IsNaN(b) || math.Abs(a-b) < 1e-9
+}
+
+func TestBuyRatio(t *testing.T) {
+	tests := []struct {
+		name        string
+		buys, sells int
+		want        float64
+	}{
+		{"普通", 3, 1, 0.75},
+		{"没有卖单", 5, 0, 1},
+		{"没有买单", 0, 4, 0},
+		{"没有成交", 0, 0, math.NaN()},
+		{"负数无效", -1, 3, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuyRatio(tt.buys, tt.sells); !same(got, tt.want) {
+				t.Errorf("BuyRatio(%d, %d) = %v, want %v", tt.buys, tt.sells, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVolumeSpike(t *testing.T) {
+	volumes := []float64{10, 20, 30, 0, 0, 0, 100}
+	nan := math.NaN()
+	tests := []struct {
+		name                    string
+		volumes                 []float64
+		i, window               int
+		volume, baseline, ratio float64
+	}{
+		{"基线为 0（最后一根）", volumes, 6, 3, 100, 0, nan},
+		{"最后一根放量 6 倍", []float64{1, 2, 3, 12}, 3, 3, 12, 2, 6},
+		{"i 小于 window 时基线不足", volumes, 2, 3, 30, nan, nan},
+		{"i 等于 window 时刚好足够", volumes, 3, 3, 0, 20, 0},
+		{"i 越界", volumes, 7, 3, nan, nan, nan},
+		{"i 为负", volumes, -1, 3, nan, nan, nan},
+		{"window 为 0", volumes, 6, 0, nan, nan, nan},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, b, r := VolumeSpike(tt.volumes, tt.i, tt.window)
+			if !same(v, tt.volume) || !same(b, tt.baseline) || !same(r, tt.ratio) {
+				t.Errorf("VolumeSpike(i=%d, window=%d) = (%v, %v, %v), want (%v, %v, %v)",
+					tt.i, tt.window, v, b, r, tt.volume, tt.baseline, tt.ratio)
+			}
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package geckoterminal
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
 	"time"
 )
 
@@ -47,8 +50,8 @@ type OHLCV struct {
 	Volume    float64 // 交易量
 }
 
-// GetOHLCV 获取代币的K线数据
-func GetOHLCV(network, poolAddress, timeframe string, options map[string]string, proxyURL string) ([]OHLCV, *MetaData, error) {
+// GetOHLCV 获取代币的K线数据，ctx 取消时中断请求
+func GetOHLCV(ctx context.Context, network, poolAddress, timeframe string, options map[string]string, proxyURL string) ([]OHLCV, *MetaData, error) {
 	// 构建请求URL
 	baseURL := fmt.Sprintf("https://api.geckoterminal.com/api/v2/networks/%s/pools/%s/ohlcv/%s",
 		network, poolAddress, timeframe)
@@ -87,7 +90,7 @@ func GetOHLCV(network, poolAddress, timeframe string, options map[string]string,
 	}
 
 	// 创建HTTP请求
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("创建HTTP请求失败: %v", err)
 	}
@@ -97,7 +100,9 @@ func GetOHLCV(network, poolAddress, timeframe string, options map[string]string,
 	req.Header.Add("User-Agent", "GeckoTerminalClient/1.0")
 
 	// 发送HTTP请求
+	start := time.Now()
 	resp, err := client.Do(req)
+	metrics.ObserveRequest(metrics.ServiceGecko, start, resp, err)
 	if err != nil {
 		return nil, nil, fmt.Errorf("HTTP请求失败: %v", err)
 	}
@@ -117,8 +122,8 @@ func GetOHLCV(network, poolAddress, timeframe string, options map[string]string,
 	// 解析JSON响应
 	var response OHLCVResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		// 打印原始响应以便调试
-		fmt.Printf("原始响应: %s\n", string(body))
+		// 记录原始响应以便调试
+		logging.Component("geckoterminal").Debug("原始响应", "body", string(body))
 		return nil, nil, fmt.Errorf("解析JSON失败: %v", err)
 	}
 
@@ -0,0 +1,68 @@
+package geckoterminal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"onchain-energe-SRSI/metrics"
+	"strings"
+	"time"
+)
+
+type tokenPoolsResponse struct {
+	Data []struct {
+		Attributes struct {
+			Address string `json:"address"`
+			Name    string `json:"name"` // 形如 "SYM / SOL"
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// GetTopPool 查询代币流动性最高的池子，返回池地址与代币符号
+func GetTopPool(ctx context.Context, network, tokenAddress, proxyURL string) (poolAddress, symbol string, err error) {
+	requestURL := fmt.Sprintf("https://api.geckoterminal.com/api/v2/networks/%s/tokens/%s/pools?page=1",
+		network, tokenAddress)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return "", "", fmt.Errorf("解析代理URL失败: %v", err)
+		}
+		client.Transport = &http.Transport{Proxy: http.ProxyURL(proxy)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("创建HTTP请求失败: %v", err)
+	}
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", "GeckoTerminalClient/1.0")
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	metrics.ObserveRequest(metrics.ServiceGecko, start, resp, err)
+	if err != nil {
+		return "", "", fmt.Errorf("HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("HTTP请求返回非成功状态码: %d", resp.StatusCode)
+	}
+
+	var response tokenPoolsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", "", fmt.Errorf("解析JSON失败: %v", err)
+	}
+	if len(response.Data) == 0 {
+		return "", "", fmt.Errorf("未找到 %s 的交易池", tokenAddress)
+	}
+
+	// 接口按流动性降序返回，取第一个
+	pool := response.Data[0].Attributes
+	symbol, _, _ = strings.Cut(pool.Name, " / ")
+	return pool.Address, strings.TrimSpace(symbol), nil
+}
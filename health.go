@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/telegram"
+	"time"
+)
+
+// startTime 进程启动时间；依赖在启动后的阈值时间内尚未成功也视为就绪
+var startTime = time.Now()
+
+// dependencyCheck /readyz 中单个依赖的状态
+type dependencyCheck struct {
+	OK            bool       `json:"ok"`
+	LastSuccess   *time.Time `json:"last_success,omitempty"`
+	AgeSec        int64      `json:"age_sec"` // 距最近一次成功（从未成功时为距启动）的秒数
+	StaleAfterSec int        `json:"stale_after_sec"`
+}
+
+// scanCheck 最近一次扫描的状态
+type scanCheck struct {
+	dependencyCheck
+	LastStatus string `json:"last_status,omitempty"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
+type readiness struct {
+	Status string         `json:"status"` // ok / unavailable
+	Checks map[string]any `json:"checks"`
+}
+
+func checkSince(last time.Time, staleSec int) dependencyCheck {
+	c := dependencyCheck{StaleAfterSec: staleSec}
+	ref := startTime
+	if !last.IsZero() {
+		c.LastSuccess = &last
+		ref = last
+	}
+	age := time.Since(ref)
+	c.AgeSec = int64(age.Seconds())
+	c.OK = age <= time.Duration(staleSec)*time.Second
+	return c
+}
+
+// checkScan 最近一次扫描需已完成，且最近一次完成的扫描未过期
+func checkScan(staleSec int) scanCheck {
+	scanHistory.RLock()
+	var last, lastCompleted *scanRecord
+	for i := len(scanHistory.records) - 1; i >= 0; i-- {
+		rec := &scanHistory.records[i]
+		if last == nil && rec.Status != scanPaused {
+			last = rec
+		}
+		if rec.Status == scanCompleted {
+			lastCompleted = rec
+			break
+		}
+	}
+	c := scanCheck{}
+	var completedAt time.Time
+	if lastCompleted != nil {
+		completedAt = lastCompleted.End
+	}
+	if last != nil {
+		c.LastStatus, c.LastError = last.Status, last.Error
+	}
+	scanHistory.RUnlock()
+
+	c.dependencyCheck = checkSince(completedAt, staleSec)
+	// 尚未完成过扫描时不就绪；暂停期间只看是否过期
+	c.OK = c.OK && !completedAt.IsZero() && (last == nil || last.Status == scanCompleted)
+	return c
+}
+
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":     "ok",
+		"uptime_sec": int64(time.Since(startTime).Seconds()),
+	})
+}
+
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	h := config.Health
+	checks := map[string]any{}
+	ok := true
+	add := func(name string, c dependencyCheck) {
+		checks[name] = c
+		ok = ok && c.OK
+	}
+	add("axiom", checkSince(metrics.LastSuccess(metrics.ServiceAxiom), h.AxiomStaleSec))
+	add("geckoterminal", checkSince(metrics.LastSuccess(metrics.ServiceGecko), h.GeckoStaleSec))
+	add("ban_list", checkSince(metrics.LastSuccess(metrics.ServiceBanList), h.BanListStaleSec))
+	add("telegram", checkSince(metrics.LastSuccess(metrics.ServiceTelegram), h.TelegramStaleSec))
+	scan := checkScan(h.ScanStaleSec)
+	checks["scan"] = scan
+	ok = ok && scan.OK
+
+	resp := readiness{Status: "ok", Checks: checks}
+	status := http.StatusOK
+	if !ok {
+		resp.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
+
+// discoveryFailures 连续榜单获取失败的轮数，仅由 recordScan 修改
+var discoveryFailures int
+
+// trackDiscovery 连续失败达到阈值时发送一次自检警报，恢复后再通知一次
+func trackDiscovery(rec scanRecord) {
+	threshold := config.Health.DiscoveryFailAlert
+	switch rec.Status {
+	case scanFailed:
+		discoveryFailures++
+		if discoveryFailures == threshold {
+			sendSelfAlert(fmt.Sprintf("⚠️ 榜单获取已连续失败 %d 轮\n最近错误: %s", discoveryFailures, rec.Error))
+		}
+	case scanCompleted:
+		if discoveryFailures >= threshold {
+			sendSelfAlert(fmt.Sprintf("✅ 榜单获取已恢复（此前连续失败 %d 轮）", discoveryFailures))
+		}
+		discoveryFailures = 0
+	}
+}
+
+func sendSelfAlert(text string) {
+	slog.Warn("发送自检警报", "text", text)
+	err := telegram.Enqueue(telegram.OutboundMessage{
+		BotToken: config.BotToken,
+		ChatID:   config.ChatID,
+		Text:     text,
+		Priority: telegram.PriorityHigh,
+	})
+	if err != nil {
+		slog.Error("发送自检警报失败", "err", err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/stream"
+	"onchain-energe-SRSI/types"
+	"sync"
+	"time"
+)
+
+// 历史记录保留条数
+const (
+	maxScanHistory   = 1000
+	maxSignalHistory = 5000
+)
+
+// 扫描状态
+const (
+	scanCompleted = "completed"
+	scanFailed    = "failed"
+	scanPaused    = "paused"
+	scanCanceled  = "canceled" // 退出时中断
+	scanTimeout   = "timeout"  // 超过单次扫描期限
+)
+
+// scanRecord 一轮扫描的记录
+type scanRecord struct {
+	ID         int64     `json:"id"`
+	Start      time.Time `json:"start"`
+	BarClose   time.Time `json:"bar_close,omitzero"` // 触发本轮的 1 分钟收盘时间
+	End        time.Time `json:"end"`
+	DurationMs int64     `json:"duration_ms"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	Discovered int       `json:"discovered"` // 榜单 + 关注列表
+	Banned     int       `json:"banned"`
+	Evaluated  int       `json:"evaluated"`
+	Signals    int       `json:"signals"`
+	Exits      int       `json:"exits"` // XSELL 离场信号
+}
+
+var (
+	scanHistory = struct {
+		sync.RWMutex
+		seq     int64
+		records []scanRecord
+	}{}
+
+	signalHistory = struct {
+		sync.RWMutex
+		signals []types.Signal
+	}{}
+)
+
+// nextScanID 分配扫描编号，日志中的 scan_id 与 /api/scans 一致
+func nextScanID() int64 {
+	scanHistory.Lock()
+	defer scanHistory.Unlock()
+	scanHistory.seq++
+	return scanHistory.seq
+}
+
+// recordScan 保存扫描记录、更新 /status 概况并推送扫描事件
+func recordScan(rec scanRecord) {
+	rec.End = time.Now()
+	rec.DurationMs = rec.End.Sub(rec.Start).Milliseconds()
+	metrics.ScanDuration.Observe(rec.End.Sub(rec.Start).Seconds(), rec.Status)
+	if rec.Status != scanPaused {
+		metrics.ObserveScanTokens("discovered", rec.Discovered)
+		metrics.ObserveScanTokens("banned", rec.Banned)
+		metrics.ObserveScanTokens("evaluated", rec.Evaluated)
+	}
+
+	scanHistory.Lock()
+	scanHistory.records = append(scanHistory.records, rec)
+	if len(scanHistory.records) > maxScanHistory {
+		scanHistory.records = scanHistory.records[len(scanHistory.records)-maxScanHistory:]
+	}
+	scanHistory.Unlock()
+
+	if rec.Status == scanCompleted {
+		scanStatus.Lock()
+		scanStatus.LastStart = rec.Start
+		scanStatus.LastEnd = rec.End
+		scanStatus.Discovered = rec.Discovered
+		scanStatus.Evaluated = rec.Evaluated
+		scanStatus.Signals = rec.Signals
+		scanStatus.TotalSignals += rec.Signals
+		scanStatus.Unlock()
+	}
+	trackDiscovery(rec)
+	events.Publish(stream.EventScan, "", rec)
+}
+
+// recordSignal 保存信号历史（不含图表）
+func recordSignal(sig types.Signal) {
+	sig.Chart = nil
+	signalHistory.Lock()
+	defer signalHistory.Unlock()
+	signalHistory.signals = append(signalHistory.signals, sig)
+	if len(signalHistory.signals) > maxSignalHistory {
+		signalHistory.signals = signalHistory.signals[len(signalHistory.signals)-maxSignalHistory:]
+	}
+}
@@ -0,0 +1,24 @@
+package holders
+
+import (
+	"context"
+	"onchain-energe-SRSI/types"
+)
+
+// Axiom 使用榜单接口已返回的字段（持币人数、前十占比），不发起请求
+type Axiom struct{}
+
+func (Axiom) Name() string { return "axiom" }
+
+func (Axiom) Fetch(_ context.Context, item types.TokenItem) (Snapshot, error) {
+	s := Snapshot{
+		HolderCount:     item.HolderCount,
+		Top10Pct:        item.Top10Hoders,
+		SmartDegenCount: item.SmartDegenCount,
+		RenownedCount:   item.RenownedCount,
+	}
+	if s == (Snapshot{}) {
+		return s, ErrNotFound
+	}
+	return s, nil
+}
@@ -0,0 +1,38 @@
+package holders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"onchain-energe-SRSI/types"
+	"os"
+)
+
+// Fixture 从 JSON 文件读取的固定数据，用于回放、测试或手动补充聪明钱数量
+type Fixture map[string]Snapshot // 代币地址 -> 数据
+
+// LoadFixture 文件格式 {"<代币地址>": {"holder_count": 1200, "top10_pct": 18.5, "smart_degen_count": 3, "renowned_count": 1}}；文件不存在时返回空 Fixture
+func LoadFixture(path string) (Fixture, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Fixture{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := Fixture{}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	return f, nil
+}
+
+func (Fixture) Name() string { return "fixture" }
+
+func (f Fixture) Fetch(_ context.Context, item types.TokenItem) (Snapshot, error) {
+	s, ok := f[item.Address]
+	if !ok {
+		return Snapshot{}, ErrNotFound
+	}
+	return s, nil
+}
@@ -0,0 +1,135 @@
+package holders
+
+import (
+	"context"
+	"errors"
+	"onchain-energe-SRSI/types"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 来源没有该代币的数据，继续尝试下一个来源
+var ErrNotFound = errors.New("无持币数据")
+
+// Snapshot 持币分布与聪明钱数量，零值表示来源未提供该项
+type Snapshot struct {
+	HolderCount     int     `json:"holder_count"`
+	Top10Pct        float64 `json:"top10_pct"` // 前十持币占比（%）
+	SmartDegenCount int     `json:"smart_degen_count"`
+	RenownedCount   int     `json:"renowned_count"`
+}
+
+// Provider 持币数据来源，测试时可替换为 Fixture
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context, item types.TokenItem) (Snapshot, error)
+}
+
+// merge 已有值优先，只补齐缺失项
+func (s *Snapshot) merge(o Snapshot) {
+	if s.HolderCount == 0 {
+		s.HolderCount = o.HolderCount
+	}
+	if s.Top10Pct == 0 {
+		s.Top10Pct = o.Top10Pct
+	}
+	if s.SmartDegenCount == 0 {
+		s.SmartDegenCount = o.SmartDegenCount
+	}
+	if s.RenownedCount == 0 {
+		s.RenownedCount = o.RenownedCount
+	}
+}
+
+func (s Snapshot) complete() bool {
+	return s.HolderCount > 0 && s.Top10Pct > 0 && s.SmartDegenCount > 0 && s.RenownedCount > 0
+}
+
+// sample 某次扫描时的持币人数
+type sample struct {
+	count int
+	at    time.Time
+}
+
+// Enricher 按顺序查询各来源补齐 TokenItem 的持币字段，并记录增长窗口内的持币人数变化
+type Enricher struct {
+	providers []Provider
+	window    time.Duration
+
+	mu      sync.Mutex
+	samples map[string][]sample // 代币地址 -> 窗口内的持币人数，时间升序
+}
+
+// New window 为持币增长的统计窗口，增长相对窗口内最早的一次记录计算；providers 靠前的优先，如 fixture 覆盖链上与 Axiom 的数据
+func New(window time.Duration, providers ...Provider) *Enricher {
+	return &Enricher{providers: providers, window: window, samples: make(map[string][]sample)}
+}
+
+// Enrich 写入 item 的持币字段并返回增长窗口内的持币增长；所有来源都失败时返回最后一个错误，item 保持不变
+func (e *Enricher) Enrich(ctx context.Context, item *types.TokenItem, now time.Time) (types.HolderGrowth, error) {
+	var (
+		snap    Snapshot
+		lastErr error
+		found   bool
+	)
+	for _, p := range e.providers {
+		if snap.complete() {
+			break
+		}
+		s, err := p.Fetch(ctx, *item)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				lastErr = err
+			}
+			continue
+		}
+		snap.merge(s)
+		found = true
+	}
+	if !found {
+		return types.HolderGrowth{}, lastErr
+	}
+
+	item.HolderCount = snap.HolderCount
+	item.Top10Hoders = snap.Top10Pct
+	item.SmartDegenCount = snap.SmartDegenCount
+	item.RenownedCount = snap.RenownedCount
+	return e.observe(item.Address, snap.HolderCount, now), nil
+}
+
+// observe 记录本次持币人数，并与窗口内最早的记录比较。
+// 窗口内没有更早的记录（首次出现、长时间未出现）或本次人数未知时 Known 为 false，由调用方决定如何处理
+func (e *Enricher) observe(address string, count int, now time.Time) types.HolderGrowth {
+	if count <= 0 {
+		return types.HolderGrowth{}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	hist := e.samples[address]
+	for len(hist) > 0 && now.Sub(hist[0].at) > e.window {
+		hist = hist[1:]
+	}
+	e.samples[address] = append(hist, sample{count: count, at: now})
+	if len(hist) == 0 {
+		return types.HolderGrowth{}
+	}
+	base := hist[0]
+	return types.HolderGrowth{
+		Known:    true,
+		Previous: base.count,
+		Delta:    count - base.count,
+		Pct:      float64(count-base.count) / float64(base.count) * 100,
+		Since:    base.at,
+	}
+}
+
+// Forget 清理超过 maxAge 未出现的代币
+func (e *Enricher) Forget(maxAge time.Duration, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for addr, hist := range e.samples {
+		if now.Sub(hist[len(hist)-1].at) > maxAge {
+			delete(e.samples, addr)
+		}
+	}
+}
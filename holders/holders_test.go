@@ -0,0 +1,119 @@
+package holders
+
+import (
+	"context"
+	"errors"
+	"onchain-energe-SRSI/types"
+	"testing"
+	"time"
+)
+
+// counter 持币人数可随时修改的来源
+type counter struct{ count int }
+
+func (*counter) Name() string { return "counter" }
+
+func (c *counter) Fetch(context.Context, types.TokenItem) (Snapshot, error) {
+	if c.count == 0 {
+		return Snapshot{}, ErrNotFound
+	}
+	return Snapshot{HolderCount: c.count}, nil
+}
+
+func TestEnrichGrowthWindow(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []struct {
+		name      string
+		min       int // 距 t0 的分钟数
+		count     int
+		wantKnown bool
+		wantPrev  int
+		wantSince int
+	}{
+		{"首次出现：增长未知", 0, 100, false, 0, 0},
+		{"1 分钟后：与窗口内最早（t0）比较", 1, 101, true, 100, 0},
+		{"30 分钟后：仍与 t0 比较而非上一轮", 30, 130, true, 100, 0},
+		{"61 分钟后：t0 移出窗口，改用 1 分钟时的记录", 61, 150, true, 101, 1},
+		{"人数未知：不记录", 62, 0, false, 0, 0},
+		{"200 分钟后：窗口内没有记录，重新开始", 200, 160, false, 0, 0},
+		{"201 分钟后", 201, 176, true, 160, 200},
+	}
+	src := &counter{}
+	e := New(time.Hour, src)
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			src.count = s.count
+			item := types.TokenItem{Address: "A"}
+			g, err := e.Enrich(context.Background(), &item, t0.Add(time.Duration(s.min)*time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g.Known != s.wantKnown {
+				t.Fatalf("Known = %v, want %v", g.Known, s.wantKnown)
+			}
+			if !s.wantKnown {
+				return
+			}
+			wantSince := t0.Add(time.Duration(s.wantSince) * time.Minute)
+			if g.Previous != s.wantPrev || g.Delta != s.count-s.wantPrev || !g.Since.Equal(wantSince) {
+				t.Errorf("growth = %+v, want 基准 %d @ %v", g, s.wantPrev, wantSince)
+			}
+			if want := float64(s.count-s.wantPrev) / float64(s.wantPrev) * 100; g.Pct != want {
+				t.Errorf("Pct = %v, want %v", g.Pct, want)
+			}
+		})
+	}
+}
+
+func TestEnrichMergeAndErrors(t *testing.T) {
+	fixture := Fixture{"A": {Top10Pct: 12}}
+	axiomItem := types.TokenItem{Address: "A", HolderCount: 900, Top10Hoders: 40, SmartDegenCount: 2}
+	e := New(time.Hour, fixture, Axiom{})
+	item := axiomItem
+	if _, err := e.Enrich(context.Background(), &item, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// fixture 优先，缺失项由 Axiom 补齐
+	if item.Top10Hoders != 12 || item.HolderCount != 900 || item.SmartDegenCount != 2 {
+		t.Errorf("item = %+v", item)
+	}
+
+	failing := errors.New("rpc down")
+	e = New(time.Hour, errProvider{failing}, Fixture{})
+	item = types.TokenItem{Address: "B", HolderCount: 5}
+	if _, err := e.Enrich(context.Background(), &item, time.Now()); !errors.Is(err, failing) {
+		t.Errorf("err = %v, want %v", err, failing)
+	}
+	if item.HolderCount != 5 {
+		t.Error("全部失败时 item 应保持不变")
+	}
+}
+
+type errProvider struct{ err error }
+
+func (errProvider) Name() string { return "err" }
+
+func (p errProvider) Fetch(context.Context, types.TokenItem) (Snapshot, error) {
+	return Snapshot{}, p.err
+}
+
+func TestForget(t *testing.T) {
+	t0 := time.Now()
+	src := &counter{count: 10}
+	e := New(time.Hour, src)
+	for _, addr := range []string{"old", "new"} {
+		item := types.TokenItem{Address: addr}
+		at := t0
+		if addr == "old" {
+			at = t0.Add(-25 * time.Hour)
+		}
+		e.Enrich(context.Background(), &item, at)
+	}
+	e.Forget(24*time.Hour, t0)
+	if _, ok := e.samples["old"]; ok {
+		t.Error("超过 maxAge 的代币应被清理")
+	}
+	if _, ok := e.samples["new"]; !ok {
+		t.Error("近期出现的代币不应被清理")
+	}
+}
@@ -0,0 +1,115 @@
+package holders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/types"
+	"strconv"
+	"time"
+)
+
+// SolanaRPC 通过 getTokenLargestAccounts / getTokenSupply 估算前十持币占比。
+// 目前只是占位实现：持币人数需要 getProgramAccounts 全量扫描，聪明钱需要地址标签库，均不提供；
+// 最大账户中包含交易池金库，占比会偏高
+type SolanaRPC struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewSolanaRPC proxyURL 为空时直连
+func NewSolanaRPC(rpcURL, proxyURL string) (*SolanaRPC, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("代理地址无效: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return &SolanaRPC{URL: rpcURL, Client: &http.Client{Timeout: 10 * time.Second, Transport: transport}}, nil
+}
+
+func (*SolanaRPC) Name() string { return "solana_rpc" }
+
+func (r *SolanaRPC) Fetch(ctx context.Context, item types.TokenItem) (Snapshot, error) {
+	if item.Chain != "solana" {
+		return Snapshot{}, ErrNotFound
+	}
+	var largest struct {
+		Value []struct {
+			Amount string `json:"amount"`
+		} `json:"value"`
+	}
+	if err := r.call(ctx, "getTokenLargestAccounts", item.Address, &largest); err != nil {
+		return Snapshot{}, err
+	}
+	var supply struct {
+		Value struct {
+			Amount string `json:"amount"`
+		} `json:"value"`
+	}
+	if err := r.call(ctx, "getTokenSupply", item.Address, &supply); err != nil {
+		return Snapshot{}, err
+	}
+
+	total, err := strconv.ParseFloat(supply.Value.Amount, 64)
+	if err != nil || total <= 0 {
+		return Snapshot{}, fmt.Errorf("总供应量无效: %q", supply.Value.Amount)
+	}
+	top := 0.0
+	for i, a := range largest.Value {
+		if i == 10 {
+			break
+		}
+		v, err := strconv.ParseFloat(a.Amount, 64)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("持仓数量无效: %q", a.Amount)
+		}
+		top += v
+	}
+	return Snapshot{Top10Pct: top / total * 100}, nil
+}
+
+// call 单个参数的 JSON-RPC 调用
+func (r *SolanaRPC) call(ctx context.Context, method, param string, result any) error {
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method, "params": []string{param}})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := r.Client.Do(req)
+	metrics.ObserveRequest(metrics.ServiceSolana, start, resp, err)
+	if err != nil {
+		return fmt.Errorf("%s 请求失败: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s HTTP 状态码错误: %d", method, resp.StatusCode)
+	}
+
+	var out struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("%s 解析失败: %w", method, err)
+	}
+	if out.Error != nil {
+		return fmt.Errorf("%s 返回错误 %d: %s", method, out.Error.Code, out.Error.Message)
+	}
+	return json.Unmarshal(out.Result, result)
+}
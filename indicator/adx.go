@@ -0,0 +1,59 @@
+package indicator
+
+import (
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+)
+
+// DMI 趋向指标，与输入等长，数据不足处为 NaN
+type DMI struct {
+	PlusDI, MinusDI []float64
+	ADX             []float64
+}
+
+// ADX Wilder 的 +DI / -DI 与 ADX：DI 从第 period 根起有值，ADX 从第 2*period-1 根起有值
+func ADX(candles []geckoterminal.OHLCV, period int) DMI {
+	n := len(candles)
+	d := DMI{PlusDI: nanSlice(n), MinusDI: nanSlice(n), ADX: nanSlice(n)}
+	if period <= 0 || n <= period {
+		return d
+	}
+
+	// 第 0 根没有前值，从第 1 根开始计算方向运动
+	tr := TrueRange(candles)[1:]
+	plusDM := make([]float64, n-1)
+	minusDM := make([]float64, n-1)
+	for i := 1; i < n; i++ {
+		up := candles[i].High - candles[i-1].High
+		down := candles[i-1].Low - candles[i].Low
+		if up > down && up > 0 {
+			plusDM[i-1] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i-1] = down
+		}
+	}
+	atr := wilder(tr, period)
+	sp := wilder(plusDM, period)
+	sm := wilder(minusDM, period)
+
+	dx := make([]float64, 0, n)
+	for j := period - 1; j < n-1; j++ {
+		i := j + 1
+		if atr[j] == 0 {
+			d.PlusDI[i], d.MinusDI[i] = 0, 0
+		} else {
+			d.PlusDI[i] = 100 * sp[j] / atr[j]
+			d.MinusDI[i] = 100 * sm[j] / atr[j]
+		}
+		sum := d.PlusDI[i] + d.MinusDI[i]
+		if sum == 0 {
+			dx = append(dx, 0)
+		} else {
+			dx = append(dx, 100*math.Abs(d.PlusDI[i]-d.MinusDI[i])/sum)
+		}
+	}
+	adx := wilder(dx, period)
+	copy(d.ADX[period:], adx)
+	return d
+}
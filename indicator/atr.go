@@ -0,0 +1,57 @@
+package indicator
+
+import (
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+)
+
+// TrueRange 每根 K 线的真实波幅，第一根为最高价减最低价
+func TrueRange(candles []geckoterminal.OHLCV) []float64 {
+	tr := make([]float64, len(candles))
+	for i, c := range candles {
+		tr[i] = c.High - c.Low
+		if i > 0 {
+			prev := candles[i-1].Close
+			tr[i] = max(tr[i], math.Abs(c.High-prev), math.Abs(c.Low-prev))
+		}
+	}
+	return tr
+}
+
+// ATR Wilder 平均真实波幅，与 candles 等长；前 period-1 个为 NaN，第 period 个为前 period 个真实波幅的均值
+func ATR(candles []geckoterminal.OHLCV, period int) []float64 {
+	return wilder(TrueRange(candles), period)
+}
+
+// wilder Wilder 平滑：首值为前 period 个的均值，之后 (prev*(period-1)+v)/period
+func wilder(values []float64, period int) []float64 {
+	res := nanSlice(len(values))
+	if period <= 0 || len(values) < period {
+		return res
+	}
+	sum := 0.0
+	for i := 0; i < period; i++ {
+		sum += values[i]
+	}
+	res[period-1] = sum / float64(period)
+	for i := period; i < len(values); i++ {
+		res[i] = (res[i-1]*float64(period-1) + values[i]) / float64(period)
+	}
+	return res
+}
+
+func nanSlice(n int) []float64 {
+	res := make([]float64, n)
+	for i := range res {
+		res[i] = math.NaN()
+	}
+	return res
+}
+
+// Last 序列最后一个值，空序列返回 NaN
+func Last(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	return values[len(values)-1]
+}
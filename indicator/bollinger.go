@@ -0,0 +1,43 @@
+package indicator
+
+import (
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+)
+
+// Bands 布林带，与输入等长，前 period-1 个为 NaN
+type Bands struct {
+	Middle, Upper, Lower []float64
+}
+
+// Width 第 i 根的带宽（上下轨距离 / 中轨）
+func (b Bands) Width(i int) float64 {
+	return (b.Upper[i] - b.Lower[i]) / b.Middle[i]
+}
+
+// PercentB 收盘价在带内的位置，0 为下轨，1 为上轨
+func (b Bands) PercentB(i int, close float64) float64 {
+	return (close - b.Lower[i]) / (b.Upper[i] - b.Lower[i])
+}
+
+// Bollinger 收盘价的 period 均线 ± k 倍总体标准差
+func Bollinger(candles []geckoterminal.OHLCV, period int, k float64) Bands {
+	n := len(candles)
+	b := Bands{Middle: nanSlice(n), Upper: nanSlice(n), Lower: nanSlice(n)}
+	if period <= 0 {
+		return b
+	}
+	for i := period - 1; i < n; i++ {
+		sum, sq := 0.0, 0.0
+		for _, c := range candles[i-period+1 : i+1] {
+			sum += c.Close
+			sq += c.Close * c.Close
+		}
+		mean := sum / float64(period)
+		std := math.Sqrt(max(sq/float64(period)-mean*mean, 0))
+		b.Middle[i] = mean
+		b.Upper[i] = mean + k*std
+		b.Lower[i] = mean - k*std
+	}
+	return b
+}
@@ -0,0 +1,64 @@
+package indicator
+
+// EMA 指数移动平均，逐根递推。
+// 起点与 utils.CalculateEMA 一致：批量 Seed 的数据少于周期时取全部数据的均值，否则取第一个值
+type EMA struct {
+	period int
+	k      float64
+	value  float64
+	n      int
+}
+
+func NewEMA(period int) *EMA {
+	return &EMA{period: period, k: 2.0 / float64(period+1)}
+}
+
+// Seed 依次喂入历史数据，返回自身便于链式调用。
+// 空的 EMA 按 EMASeries 确定起点；之后再 Update 只继续递推，不回头修正起点
+func (e *EMA) Seed(values []float64) *EMA {
+	if e.n > 0 || len(values) == 0 {
+		for _, v := range values {
+			e.Update(v)
+		}
+		return e
+	}
+	e.value, e.n = Last(EMASeries(values, e.period)), len(values)
+	return e
+}
+
+// Update 推进一根 K 线
+func (e *EMA) Update(v float64) float64 {
+	if e.n == 0 {
+		e.value = v
+	} else {
+		e.value += (v - e.value) * e.k
+	}
+	e.n++
+	return e.value
+}
+
+func (e *EMA) Value() float64 { return e.value }
+
+// Count 已喂入的数据个数
+func (e *EMA) Count() int { return e.n }
+
+// EMASeries 整段数据的 EMA，与输入等长：数据少于周期时以全部数据的均值为起点，否则以第一个值为起点
+func EMASeries(values []float64, period int) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	res := make([]float64, len(values))
+	k := 2.0 / float64(period+1)
+	res[0] = values[0]
+	if len(values) < period {
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		res[0] = sum / float64(len(values))
+	}
+	for i := 1; i < len(values); i++ {
+		res[i] = res[i-1] + (values[i]-res[i-1])*k
+	}
+	return res
+}
@@ -0,0 +1,317 @@
+package indicator
+
+import (
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+	"testing"
+)
+
+// hlc 只关心最高、最低、收盘价的 K 线
+func hlc(v ...[3]float64) []geckoterminal.OHLCV {
+	res := make([]geckoterminal.OHLCV, len(v))
+	for i, x := range v {
+		res[i] = geckoterminal.OHLCV{Timestamp: int64(i) * 300, Open: x[2], High: x[0], Low: x[1], Close: x[2]}
+	}
+	return res
+}
+
+// cv 最高 = 最低 = 收盘的 K 线，典型价格即收盘价
+func cv(ts int64, close, volume float64) geckoterminal.OHLCV {
+	return geckoterminal.OHLCV{Timestamp: ts, Open: close, High: close, Low: close, Close: close, Volume: volume}
+}
+
+var nan = math.NaN()
+
+// assertSeries 逐项比较，NaN 只与 NaN 相等
+func assertSeries(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s 长度 = %d, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) || (!math.IsNaN(want[i]) && math.Abs(got[i]-want[i]) > 1e-9) {
+			t.Errorf("%s[%d] = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+// trend 先涨后跌的 5 根 K 线，真实波幅 [2 3 3 4 4]，末根有向上跳空后回落
+var trend = hlc(
+	[3]float64{10, 8, 9},
+	[3]float64{12, 9, 11},
+	[3]float64{13, 10, 12},
+	[3]float64{12, 8, 9},
+	[3]float64{11, 7, 8},
+)
+
+func TestATR(t *testing.T) {
+	gap := hlc(
+		[3]float64{10, 8, 9},
+		[3]float64{11, 9, 10},
+		[3]float64{13, 10, 12},
+		[3]float64{12, 7, 8},
+		[3]float64{15, 14, 14.5}, // 跳空：真实波幅取与前收的距离
+	)
+	tests := []struct {
+		name    string
+		candles []geckoterminal.OHLCV
+		period  int
+		want    []float64
+	}{
+		{"Wilder 平滑", gap, 3, []float64{nan, nan, 7.0 / 3, 29.0 / 9, 121.0 / 27}},
+		{"周期 2", trend, 2, []float64{nan, 2.5, 2.75, 3.375, 3.6875}},
+		{"数据不足周期", gap[:2], 3, []float64{nan, nan}},
+		{"周期无效", gap[:2], 0, []float64{nan, nan}},
+		{"空输入", nil, 3, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "ATR", ATR(tt.candles, tt.period), tt.want)
+		})
+	}
+	assertSeries(t, "TrueRange", TrueRange(gap), []float64{2, 2, 3, 5, 7})
+}
+
+func TestBollinger(t *testing.T) {
+	candles := []geckoterminal.OHLCV{cv(0, 1, 0), cv(300, 2, 0), cv(600, 3, 0), cv(900, 4, 0)}
+	std := math.Sqrt(2.0 / 3) // 1,2,3 的总体标准差
+	tests := []struct {
+		name              string
+		candles           []geckoterminal.OHLCV
+		period            int
+		mid, upper, lower []float64
+	}{
+		{"周期 3", candles, 3,
+			[]float64{nan, nan, 2, 3},
+			[]float64{nan, nan, 2 + 2*std, 3 + 2*std},
+			[]float64{nan, nan, 2 - 2*std, 3 - 2*std}},
+		{"数据不足周期", candles, 5,
+			[]float64{nan, nan, nan, nan},
+			[]float64{nan, nan, nan, nan},
+			[]float64{nan, nan, nan, nan}},
+		{"周期无效", candles[:1], 0, []float64{nan}, []float64{nan}, []float64{nan}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Bollinger(tt.candles, tt.period, 2)
+			assertSeries(t, "Middle", b.Middle, tt.mid)
+			assertSeries(t, "Upper", b.Upper, tt.upper)
+			assertSeries(t, "Lower", b.Lower, tt.lower)
+		})
+	}
+
+	b := Bollinger(candles, 3, 2)
+	if got, want := b.Width(3), 4*std/3; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Width = %v, want %v", got, want)
+	}
+	if got := b.PercentB(3, 3); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("PercentB(中轨) = %v, want 0.5", got)
+	}
+	// 价格不变时带宽为 0
+	flat := Bollinger([]geckoterminal.OHLCV{cv(0, 5, 0), cv(300, 5, 0)}, 2, 2)
+	if flat.Width(1) != 0 {
+		t.Errorf("横盘带宽 = %v, want 0", flat.Width(1))
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	const day = 86400
+	tests := []struct {
+		name    string
+		candles []geckoterminal.OHLCV
+		want    []float64
+	}{
+		{"按 UTC 日重新累计", []geckoterminal.OHLCV{
+			cv(day-300, 10, 1),
+			cv(day, 20, 1), // 新的一天
+			cv(day+300, 30, 3),
+			cv(day+600, 40, 0), // 无成交量不改变均价
+		}, []float64{10, 20, 27.5, 27.5}},
+		{"开盘无成交量取典型价格", []geckoterminal.OHLCV{
+			{Timestamp: 0, High: 12, Low: 6, Close: 9},
+			cv(300, 20, 2),
+		}, []float64{9, 20}},
+		{"空输入", nil, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "VWAP", VWAP(tt.candles), tt.want)
+		})
+	}
+}
+
+func TestRollingVWAP(t *testing.T) {
+	candles := []geckoterminal.OHLCV{cv(0, 10, 1), cv(300, 20, 1), cv(600, 30, 3), cv(900, 40, 0), cv(1200, 50, 0)}
+	tests := []struct {
+		name   string
+		window int
+		input  []geckoterminal.OHLCV
+		want   []float64
+	}{
+		{"窗口 2", 2, candles, []float64{nan, 15, 27.5, 30, 50}}, // 最后一窗无成交量取典型价格
+		{"窗口 1 即典型价格", 1, candles[:2], []float64{10, 20}},
+		{"数据不足窗口", 6, candles, []float64{nan, nan, nan, nan, nan}},
+		{"窗口无效", 0, candles[:1], []float64{nan}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "RollingVWAP", RollingVWAP(tt.input, tt.window), tt.want)
+		})
+	}
+}
+
+func TestOBV(t *testing.T) {
+	candles := []geckoterminal.OHLCV{cv(0, 10, 1), cv(300, 11, 2), cv(600, 11, 3), cv(900, 9, 4), cv(1200, 12, 5)}
+	obv := OBV(candles)
+	assertSeries(t, "OBV", obv, []float64{0, 2, 2, -2, 3})
+	assertSeries(t, "OBV(单根)", OBV(candles[:1]), []float64{0})
+
+	tests := []struct {
+		n    int
+		want float64
+	}{
+		{1, 5},
+		{2, 0.5},
+		{4, 0.75},
+		{5, nan}, // 数据不足
+		{0, nan},
+	}
+	for _, tt := range tests {
+		got := Slope(obv, tt.n)
+		if math.IsNaN(tt.want) != math.IsNaN(got) || (!math.IsNaN(tt.want) && math.Abs(got-tt.want) > 1e-9) {
+			t.Errorf("Slope(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestADX(t *testing.T) {
+	// +DM [2 1 0 0]，-DM [0 0 2 1]，真实波幅 [3 3 4 4]；DX [100 100/7 500/11]
+	tests := []struct {
+		name             string
+		candles          []geckoterminal.OHLCV
+		period           int
+		plus, minus, adx []float64
+	}{
+		{"周期 2", trend, 2,
+			[]float64{nan, nan, 50, 75 / 3.5, 10},
+			[]float64{nan, nan, 0, 100 / 3.5, 100 / 3.75},
+			[]float64{nan, nan, nan, 400.0 / 7, (400.0/7 + 500.0/11) / 2}},
+		{"数据不足周期", trend[:2], 2,
+			[]float64{nan, nan}, []float64{nan, nan}, []float64{nan, nan}},
+		{"周期无效", trend[:2], 0,
+			[]float64{nan, nan}, []float64{nan, nan}, []float64{nan, nan}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ADX(tt.candles, tt.period)
+			assertSeries(t, "PlusDI", d.PlusDI, tt.plus)
+			assertSeries(t, "MinusDI", d.MinusDI, tt.minus)
+			assertSeries(t, "ADX", d.ADX, tt.adx)
+		})
+	}
+}
+
+func TestSupertrend(t *testing.T) {
+	tests := []struct {
+		name    string
+		candles []geckoterminal.OHLCV
+		period  int
+		line    []float64
+		up      []bool
+	}{
+		// 下轨只上移不下移，末根收盘跌破下轨翻空，改用已收紧的上轨
+		{"跌破下轨翻空", trend, 2,
+			[]float64{nan, 8, 8.75, 8.75, 12.6875},
+			[]bool{false, true, true, true, false}},
+		{"数据不足周期", trend[:2], 3,
+			[]float64{nan, nan},
+			[]bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := Supertrend(tt.candles, tt.period, 1)
+			assertSeries(t, "Line", st.Line, tt.line)
+			for i := range tt.up {
+				if st.Up[i] != tt.up[i] {
+					t.Errorf("Up[%d] = %v, want %v", i, st.Up[i], tt.up[i])
+				}
+			}
+		})
+	}
+}
+
+// wilderCloses Wilder《New Concepts in Technical Trading Systems》中 14 周期 RSI 的示例数据
+var wilderCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21,
+}
+
+func TestRSI(t *testing.T) {
+	// 第 15 个收盘价起有值；常见表格因中间值四舍五入略有出入（70.53、66.32…）
+	want := []float64{70.4641, 66.2496, 66.4809, 69.3469, 66.2947, 57.9150, 62.8807}
+	r := NewRSI(14)
+	var got []float64
+	for i, c := range wilderCloses {
+		v := r.Update(c)
+		if r.Ready() != (i >= 14) {
+			t.Fatalf("第 %d 个收盘价 Ready = %v", i, r.Ready())
+		}
+		if !r.Ready() {
+			if v != 50 {
+				t.Fatalf("数据不足时 RSI = %v, want 50", v)
+			}
+			continue
+		}
+		got = append(got, v)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-3 {
+			t.Errorf("RSI[%d] = %.4f, want %.4f", i+14, got[i], want[i])
+		}
+	}
+	if v := NewRSI(14).Seed(wilderCloses).Value(); v != got[len(got)-1] {
+		t.Errorf("Seed 后 Value = %v, want %v", v, got[len(got)-1])
+	}
+
+	tests := []struct {
+		name   string
+		closes []float64
+		want   float64
+	}{
+		{"只涨不跌", []float64{1, 2, 3, 4}, 100},
+		{"只跌不涨", []float64{4, 3, 2, 1}, 0},
+		{"横盘", []float64{2, 2, 2, 2}, 50},
+		{"涨跌相等", []float64{1, 2, 1}, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewRSI(len(tt.closes) - 1).Seed(tt.closes).Value(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RSI = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSMA(t *testing.T) {
+	s := NewSMA(3)
+	if s.Value() != 0 || s.Ready() {
+		t.Fatal("空 SMA 应为 0 且未就绪")
+	}
+	// 不足周期时取已有数据的均值，之后滑动窗口
+	values := []float64{2, 4, 6, 8, 10, 3}
+	want := []float64{2, 3, 4, 6, 8, 7}
+	for i, v := range values {
+		if got := s.Update(v); math.Abs(got-want[i]) > 1e-9 {
+			t.Errorf("SMA[%d] = %v, want %v", i, got, want[i])
+		}
+		if s.Ready() != (i >= 2) {
+			t.Errorf("第 %d 个值 Ready = %v", i, s.Ready())
+		}
+	}
+	if s.Count() != len(values) {
+		t.Errorf("Count = %d, want %d", s.Count(), len(values))
+	}
+	if got := NewSMA(3).Seed(values).Value(); got != s.Value() {
+		t.Errorf("Seed 后 Value = %v, want %v", got, s.Value())
+	}
+}
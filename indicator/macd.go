@@ -0,0 +1,89 @@
+package indicator
+
+import "math"
+
+// MACD DIF = EMA(fast) - EMA(slow)，DEA = EMA(DIF, signal)，柱值 = DIF - DEA。
+// 保留全部 DIF / 柱值历史，可按任意柱偏移读取
+type MACD struct {
+	fastPeriod int
+	fast, slow *EMA
+	signal     *EMA
+	dif, hist  []float64 // 末尾为最新
+}
+
+func NewMACD(fastPeriod, slowPeriod, signalPeriod int) *MACD {
+	return &MACD{
+		fastPeriod: fastPeriod,
+		fast:       NewEMA(fastPeriod),
+		slow:       NewEMA(slowPeriod),
+		signal:     NewEMA(signalPeriod),
+	}
+}
+
+// Seed 喂入历史收盘价；空的 MACD 整段计算，结果与 MACDSeries（即 utils.CalculateMACD）一致
+func (m *MACD) Seed(closes []float64) *MACD {
+	if m.Count() > 0 || len(closes) == 0 {
+		for _, c := range closes {
+			m.Update(c)
+		}
+		return m
+	}
+	m.dif, _, m.hist = MACDSeries(closes, m.fast.period, m.slow.period, m.signal.period)
+	m.fast.Seed(closes)
+	m.slow.Seed(closes)
+	m.signal.Seed(m.dif)
+	return m
+}
+
+// Update 推进一根 K 线，返回最新的 DIF、DEA 与柱值
+func (m *MACD) Update(close float64) (dif, signal, hist float64) {
+	dif = m.fast.Update(close) - m.slow.Update(close)
+	signal = m.signal.Update(dif)
+	hist = dif - signal
+	m.dif = append(m.dif, dif)
+	m.hist = append(m.hist, hist)
+	return dif, signal, hist
+}
+
+// Value 最新的 DIF、DEA 与柱值
+func (m *MACD) Value() (dif, signal, hist float64) {
+	if m.Count() == 0 {
+		return 0, 0, 0
+	}
+	return m.DIF(0), m.signal.Value(), m.Hist(0)
+}
+
+// DIF ago 根之前的 DIF，0 为最新；超出历史时为 NaN
+func (m *MACD) DIF(ago int) float64 { return at(m.dif, ago) }
+
+// Hist ago 根之前的柱值，0 为最新；超出历史时为 NaN
+func (m *MACD) Hist(ago int) float64 { return at(m.hist, ago) }
+
+func at(s []float64, ago int) float64 {
+	if ago < 0 || ago >= len(s) {
+		return math.NaN()
+	}
+	return s[len(s)-1-ago]
+}
+
+// Count 已喂入的 K 线数
+func (m *MACD) Count() int { return len(m.dif) }
+
+// FastPeriod 快线周期
+func (m *MACD) FastPeriod() int { return m.fastPeriod }
+
+// MACDSeries 整段收盘价的 DIF、DEA 与柱值，与输入等长，供绘图等需要完整曲线的场景使用
+func MACDSeries(closes []float64, fastPeriod, slowPeriod, signalPeriod int) (dif, dea, hist []float64) {
+	fast := EMASeries(closes, fastPeriod)
+	slow := EMASeries(closes, slowPeriod)
+	dif = make([]float64, len(closes))
+	for i := range closes {
+		dif[i] = fast[i] - slow[i]
+	}
+	dea = EMASeries(dif, signalPeriod)
+	hist = make([]float64, len(closes))
+	for i := range closes {
+		hist[i] = dif[i] - dea[i]
+	}
+	return dif, dea, hist
+}
@@ -0,0 +1,64 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMACDHistory(t *testing.T) {
+	closes := make([]float64, 30)
+	for i := range closes {
+		closes[i] = 100 + float64(i*i)*0.1
+	}
+	m := NewMACD(6, 13, 5).Seed(closes)
+	dif, _, hist := MACDSeries(closes, 6, 13, 5)
+	tests := []struct {
+		ago     int
+		wantNaN bool
+	}{
+		{0, false},
+		{8, false}, // 超过原先保留的 8 根
+		{20, false},
+		{29, false}, // 第一根
+		{30, true},  // 超出历史
+		{-1, true},
+	}
+	for _, tt := range tests {
+		d, h := m.DIF(tt.ago), m.Hist(tt.ago)
+		if tt.wantNaN {
+			if !math.IsNaN(d) || !math.IsNaN(h) {
+				t.Errorf("DIF(%d) = %v, Hist = %v, want NaN", tt.ago, d, h)
+			}
+			continue
+		}
+		i := len(closes) - 1 - tt.ago
+		if d != dif[i] || h != hist[i] {
+			t.Errorf("DIF(%d) = %v, Hist = %v, want %v, %v", tt.ago, d, h, dif[i], hist[i])
+		}
+	}
+	if m.Count() != len(closes) {
+		t.Errorf("Count = %d, want %d", m.Count(), len(closes))
+	}
+}
+
+// 数据不少于各周期时，逐根 Update 与整段 Seed 结果相同；Seed 之后继续 Update 与整段计算一致
+func TestMACDUpdateMatchesSeed(t *testing.T) {
+	closes := make([]float64, 40)
+	for i := range closes {
+		closes[i] = 100 + 5*math.Sin(float64(i)/4)
+	}
+	stream := NewMACD(6, 13, 5)
+	for _, c := range closes {
+		stream.Update(c)
+	}
+	seeded := NewMACD(6, 13, 5).Seed(closes[:30])
+	for _, c := range closes[30:] {
+		seeded.Update(c)
+	}
+	whole := NewMACD(6, 13, 5).Seed(closes)
+	for ago := 0; ago < len(closes); ago++ {
+		if math.Abs(stream.DIF(ago)-whole.DIF(ago)) > 1e-9 || math.Abs(seeded.Hist(ago)-whole.Hist(ago)) > 1e-9 {
+			t.Fatalf("ago=%d 逐根与整段不一致", ago)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package indicator
+
+// RSI Wilder 平滑：前 period 个涨跌取均值作为起点，之后按 (prev*(period-1)+cur)/period 递推
+type RSI struct {
+	period           int
+	prev             float64
+	n                int // 已喂入的收盘价个数
+	avgGain, avgLoss float64
+}
+
+func NewRSI(period int) *RSI {
+	return &RSI{period: period}
+}
+
+func (r *RSI) Seed(closes []float64) *RSI {
+	for _, c := range closes {
+		r.Update(c)
+	}
+	return r
+}
+
+func (r *RSI) Update(close float64) float64 {
+	if r.n > 0 {
+		change := close - r.prev
+		gain, loss := max(change, 0), max(-change, 0)
+		if r.n <= r.period {
+			r.avgGain += gain / float64(r.period)
+			r.avgLoss += loss / float64(r.period)
+		} else {
+			p := float64(r.period)
+			r.avgGain = (r.avgGain*(p-1) + gain) / p
+			r.avgLoss = (r.avgLoss*(p-1) + loss) / p
+		}
+	}
+	r.prev = close
+	r.n++
+	return r.Value()
+}
+
+// Value 数据不足 period+1 个收盘价时返回 50
+func (r *RSI) Value() float64 {
+	if !r.Ready() {
+		return 50
+	}
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	rs := r.avgGain / r.avgLoss
+	return 100 - 100/(1+rs)
+}
+
+// Ready 是否已有 period 个涨跌
+func (r *RSI) Ready() bool { return r.n > r.period }
@@ -0,0 +1,43 @@
+package indicator
+
+// SMA 简单移动平均，用环形缓冲保存最近 period 个值；不足 period 个时取已有数据的均值（与 utils.CalculateMA 一致）
+type SMA struct {
+	window []float64
+	next   int
+	n      int
+	sum    float64
+}
+
+func NewSMA(period int) *SMA {
+	return &SMA{window: make([]float64, period)}
+}
+
+func (s *SMA) Seed(values []float64) *SMA {
+	for _, v := range values {
+		s.Update(v)
+	}
+	return s
+}
+
+func (s *SMA) Update(v float64) float64 {
+	if s.n >= len(s.window) {
+		s.sum -= s.window[s.next]
+	}
+	s.window[s.next] = v
+	s.sum += v
+	s.next = (s.next + 1) % len(s.window)
+	s.n++
+	return s.Value()
+}
+
+func (s *SMA) Value() float64 {
+	if s.n == 0 {
+		return 0
+	}
+	return s.sum / float64(min(s.n, len(s.window)))
+}
+
+func (s *SMA) Count() int { return s.n }
+
+// Ready 是否已有完整的 period 个值
+func (s *SMA) Ready() bool { return s.n >= len(s.window) }
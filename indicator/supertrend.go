@@ -0,0 +1,56 @@
+package indicator
+
+import (
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+)
+
+// Trend Supertrend 结果，与输入等长；ATR 未就绪前 Line 为 NaN
+type Trend struct {
+	Line []float64
+	Up   []bool // true 为多头（价格在线上方）
+}
+
+// Supertrend 以 (H+L)/2 ± mult*ATR 为上下轨，收盘突破后翻转方向
+func Supertrend(candles []geckoterminal.OHLCV, period int, mult float64) Trend {
+	n := len(candles)
+	t := Trend{Line: nanSlice(n), Up: make([]bool, n)}
+	atr := ATR(candles, period)
+	var upper, lower float64
+	started := false
+	for i, c := range candles {
+		if math.IsNaN(atr[i]) {
+			continue
+		}
+		mid := (c.High + c.Low) / 2
+		bu, bl := mid+mult*atr[i], mid-mult*atr[i]
+		if !started {
+			upper, lower = bu, bl
+			t.Up[i] = c.Close >= mid
+			started = true
+		} else {
+			prevClose := candles[i-1].Close
+			// 轨道只朝趋势方向收紧，除非前一根收盘已越过
+			if bu < upper || prevClose > upper {
+				upper = bu
+			}
+			if bl > lower || prevClose < lower {
+				lower = bl
+			}
+			switch {
+			case t.Up[i-1] && c.Close < lower:
+				t.Up[i] = false
+			case !t.Up[i-1] && c.Close > upper:
+				t.Up[i] = true
+			default:
+				t.Up[i] = t.Up[i-1]
+			}
+		}
+		if t.Up[i] {
+			t.Line[i] = lower
+		} else {
+			t.Line[i] = upper
+		}
+	}
+	return t
+}
@@ -0,0 +1,82 @@
+package indicator
+
+import (
+	"math"
+	"onchain-energe-SRSI/geckoterminal"
+	"time"
+)
+
+// typical 典型价格 (H+L+C)/3
+func typical(c geckoterminal.OHLCV) float64 {
+	return (c.High + c.Low + c.Close) / 3
+}
+
+// VWAP 按 UTC 自然日分段的成交量加权均价，每天第一根 K 线重新累计；尚无成交量时取典型价格
+func VWAP(candles []geckoterminal.OHLCV) []float64 {
+	res := make([]float64, len(candles))
+	var pv, vol float64
+	var day int64 = -1
+	for i, c := range candles {
+		if d := c.Timestamp / int64(24*time.Hour/time.Second); d != day {
+			day, pv, vol = d, 0, 0
+		}
+		pv += typical(c) * c.Volume
+		vol += c.Volume
+		if vol > 0 {
+			res[i] = pv / vol
+		} else {
+			res[i] = typical(c)
+		}
+	}
+	return res
+}
+
+// RollingVWAP 最近 window 根 K 线的成交量加权均价，前 window-1 个为 NaN
+func RollingVWAP(candles []geckoterminal.OHLCV, window int) []float64 {
+	res := nanSlice(len(candles))
+	if window <= 0 {
+		return res
+	}
+	var pv, vol float64
+	for i, c := range candles {
+		pv += typical(c) * c.Volume
+		vol += c.Volume
+		if i >= window {
+			old := candles[i-window]
+			pv -= typical(old) * old.Volume
+			vol -= old.Volume
+		}
+		if i < window-1 {
+			continue
+		}
+		if vol > 0 {
+			res[i] = pv / vol
+		} else {
+			res[i] = typical(c)
+		}
+	}
+	return res
+}
+
+// OBV 能量潮：收盘上涨累加成交量，下跌减去，第一根为 0
+func OBV(candles []geckoterminal.OHLCV) []float64 {
+	res := make([]float64, len(candles))
+	for i := 1; i < len(candles); i++ {
+		res[i] = res[i-1]
+		switch {
+		case candles[i].Close > candles[i-1].Close:
+			res[i] += candles[i].Volume
+		case candles[i].Close < candles[i-1].Close:
+			res[i] -= candles[i].Volume
+		}
+	}
+	return res
+}
+
+// Slope 序列最近 n 根的平均变化，数据不足或含 NaN 时返回 NaN
+func Slope(values []float64, n int) float64 {
+	if n <= 0 || len(values) <= n {
+		return math.NaN()
+	}
+	return (values[len(values)-1] - values[len(values)-1-n]) / float64(n)
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 统一的日志属性名
+const (
+	KeyScanID    = "scan_id"
+	KeySymbol    = "symbol"
+	KeyAddress   = "address"
+	KeyTimeframe = "timeframe"
+	KeyStage     = "stage"
+	KeyComponent = "component"
+)
+
+// Options 日志输出配置
+type Options struct {
+	Level      string // debug / info / warn / error，默认 info
+	Format     string // text / json，默认 text
+	File       string // 为空时只输出到 stdout
+	MaxSizeMB  int    // 单个文件上限，超过后轮转
+	MaxBackups int    // 保留的历史文件数
+}
+
+// Setup 按配置创建 logger 并设为 slog 默认（标准库 log 也会经由它输出），返回需在退出时关闭的文件
+func Setup(opts Options) (io.Closer, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cmp.Or(opts.Level, "info"))); err != nil {
+		return nil, fmt.Errorf("日志级别无效: %s", opts.Level)
+	}
+
+	var (
+		w      io.Writer = os.Stdout
+		closer io.Closer = nopCloser{}
+	)
+	if opts.File != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0o755); err != nil {
+			return nil, fmt.Errorf("创建日志目录失败: %w", err)
+		}
+		f, err := newRotatingFile(opts.File, int64(opts.MaxSizeMB)<<20, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		w, closer = io.MultiWriter(os.Stdout, f), f
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(cmp.Or(opts.Format, "text")) {
+	case "json":
+		h = slog.NewJSONHandler(w, handlerOpts)
+	case "text":
+		h = slog.NewTextHandler(w, handlerOpts)
+	default:
+		closer.Close()
+		return nil, fmt.Errorf("日志格式无效: %s", opts.Format)
+	}
+	slog.SetDefault(slog.New(h))
+	return closer, nil
+}
+
+type ctxKey struct{}
+
+// WithLogger 将带属性的 logger 放入 ctx，沿调用链传递 scan_id、symbol 等
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// From 取出 ctx 中的 logger，没有时返回默认 logger
+func From(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// With 在 ctx 中的 logger 上追加属性
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, From(ctx).With(args...))
+}
+
+// Component 带组件名的默认 logger，用于没有 ctx 的后台任务
+func Component(name string) *slog.Logger {
+	return slog.Default().With(KeyComponent, name)
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
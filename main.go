@@ -1,18 +1,25 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"onchain-energe-SRSI/geckoterminal"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/notify"
+	"onchain-energe-SRSI/perp"
+	"onchain-energe-SRSI/schedule"
+	"onchain-energe-SRSI/stream"
 	"onchain-energe-SRSI/telegram"
+	"onchain-energe-SRSI/tracker"
 	"onchain-energe-SRSI/types"
 	"onchain-energe-SRSI/utils"
 	"os"
 	"os/signal"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,81 +31,367 @@ var (
 	config         *types.Config
 	tokenDataMap   = make(map[string]*types.TokenData)
 	tokenDataMutex sync.Mutex // 用于保护 tokenDataMap
-	progressLogger = log.New(os.Stdout, "[Screener] ", log.LstdFlags)
 	runScanRunning int32
+	scanWG         sync.WaitGroup // 进行中的 runScan
+	barSettle      *schedule.Settle
+	outcomeTracker *tracker.Tracker
+	perpLister     *perp.Lister         // 未启用合约检查时为 nil
+	events         = stream.NewHub(500) // SSE / WebSocket 推送
+	signalSeq      atomic.Int64
 	banSymbols     = []string{} //封禁区
 )
 
 func main() {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/api/latest-tg-messages", latestMessagesHandler)
-
-	go func() {
-		if err := http.ListenAndServe(":8889", corsMiddleware(mux)); err != nil {
-			log.Fatalf("HTTP服务器启动失败: %v", err)
-		}
-	}()
-
 	configFilePtr := flag.String("config", "config.json", "配置文件路径")
 	flag.Parse()
 
 	var err error
 	config, err = utils.LoadConfig(*configFilePtr)
 	if err != nil {
-		fmt.Printf("加载配置文件失败: %v\n", err)
+		slog.Error("加载配置文件失败", "err", err)
+		os.Exit(1)
+	}
+	logFile, err := logging.Setup(logging.Options{
+		Level:      config.Log.Level,
+		Format:     config.Log.Format,
+		File:       config.Log.File,
+		MaxSizeMB:  config.Log.MaxSizeMB,
+		MaxBackups: config.Log.MaxBackups,
+	})
+	if err != nil {
+		slog.Error("初始化日志失败", "err", err)
 		os.Exit(1)
 	}
-	resultsChan := make(chan types.TokenItem, 100)
+	defer logFile.Close()
+	resultsChan := make(chan types.Signal, 100)
+	setFilters(config.Filters)
+
+	// 收到退出信号时取消：停止调度、命令轮询与进行中的扫描
+	rootCtx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	telegram.SetAPIURL(config.TelegramAPIURL)
+	if err := telegram.SetProxy(config.Proxy); err != nil {
+		slog.Error("Telegram 代理配置无效", "err", err)
+		os.Exit(1)
+	}
+
+	// Telegram 出站队列
+	q := config.TelegramQueue
+	telegram.StartOutbox(telegram.OutboxConfig{
+		GlobalPerSecond:  q.GlobalPerSecond,
+		PrivatePerSecond: q.PrivatePerSecond,
+		GroupPerMinute:   q.GroupPerMinute,
+		CoalesceWindow:   time.Duration(q.CoalesceMs) * time.Millisecond,
+		MaxDigest:        q.MaxDigest,
+		QueueSize:        q.QueueSize,
+	})
+
+	// 通知路由：信号与首次警报按规则分发到各渠道
+	router, err := notify.NewRouter(config)
+	if err != nil {
+		slog.Error("通知渠道配置错误", "err", err)
+		os.Exit(1)
+	}
+	telegram.SetFirstAlertHandler(func(e telegram.FirstAlertEvent) {
+		chain := chainOfSymbol(e.Symbol)
+		events.Publish(stream.EventFirstAlert, chain, e)
+		router.Dispatch(notify.Alert{
+			Chain:  chain,
+			Tier:   notify.TierFirst,
+			Symbol: e.Symbol,
+			Text:   e.AlertText,
+			Time:   e.Time,
+		})
+	})
+	// 信号结果跟踪：各检查点回复原信号
+	outcomeTracker = tracker.New(signalPrice, func(rec tracker.Record, o tracker.Outcome) {
+		router.Dispatch(notify.Alert{
+			ReplyTo:  rec.Signal.ID,
+			Strategy: rec.Signal.Strategy,
+			Chain:    rec.Signal.Chain,
+			Tier:     notify.TierOutcome,
+			Symbol:   rec.Signal.Symbol,
+			Address:  rec.Signal.Address,
+			Text:     tracker.FormatOutcome(rec, o),
+		})
+	})
+	// 模拟盘
+	if err := startPaper(rootCtx); err != nil {
+		slog.Error("启动模拟盘失败", "err", err)
+		os.Exit(1)
+	}
+	// 币安永续合约交叉上线
+	if config.Perp.Enabled {
+		client, err := perp.NewBinance(config.Proxy)
+		if err != nil {
+			slog.Error("创建币安客户端失败", "err", err)
+			os.Exit(1)
+		}
+		perpLister = perp.New(client, config.Perp.OverrideFile, time.Duration(config.Perp.RefreshMin)*time.Minute)
+		go perpLister.Run(rootCtx)
+	}
+	// 持币与聪明钱补充
+	if err := startHolders(); err != nil {
+		slog.Error("启动持币数据补充失败", "err", err)
+		os.Exit(1)
+	}
+	// 自动下单
+	if err := startExecution(); err != nil {
+		slog.Error("启动下单执行器失败", "err", err)
+		os.Exit(1)
+	}
+	consumerDone := make(chan struct{})
+	go func() {
+		defer close(consumerDone)
+		for sig := range resultsChan {
+			sig.ID = nextSignalID()
+			// 带 ReplyTo 的 XSELL 为离场跟进，其余为新信号（多头 XBUY 或空头 XSELL）
+			if sig.ReplyTo != "" {
+				dispatchExit(router, sig)
+				submitOrder(sig)
+				continue
+			}
+			annotatePerp(rootCtx, &sig)
+			metrics.SignalsFired.Inc(sig.Strategy)
+			slog.Info("信号触发", "id", sig.ID, "strategy", sig.Strategy, "tag", sig.Tag,
+				logging.KeySymbol, sig.Symbol, logging.KeyAddress, sig.Address, "price", sig.Price)
+			outcomeTracker.Track(sig)
+			if paperEngine != nil {
+				paperEngine.Open(sig)
+			}
+			// 离场评估（XSELL）只针对多头
+			if !sig.Short {
+				watchSignal(sig)
+			}
+			submitOrder(sig)
+			recordSignal(sig)
+			events.Publish(stream.EventSignal, sig.Chain, sig)
+			router.Dispatch(notify.Alert{
+				ID:       sig.ID,
+				Strategy: sig.Strategy,
+				Chain:    sig.Chain,
+				Tier:     notify.TierSignal,
+				Symbol:   sig.Symbol,
+				Address:  sig.Address,
+				Text:     sig.Text,
+				Time:     sig.Time,
+				Photo:    sig.Chart,
+			})
+		}
+	}()
+
+	// Telegram 交互命令
+	if config.EnableCommands {
+		bot, err := telegram.NewBot(config.BotToken, config.Proxy, config.CommandChatIDs)
+		if err != nil {
+			slog.Error("创建命令机器人失败", "err", err)
+		} else {
+			registerCommands(bot)
+			go bot.Run(rootCtx)
+		}
+	}
 
 	//获取封禁区
 	chBanList := make(chan []string, 10)
-	banSymbols = utils.GetBanList()
-	go utils.StartBanListFetcher(chBanList)
+	banSymbols = utils.GetBanList(rootCtx)
+	go utils.StartBanListFetcher(rootCtx, chBanList)
 	go func() {
 		for Symbols := range chBanList {
+			publishBanListChange(banSymbols, Symbols)
 			banSymbols = Symbols
 		}
 	}()
 
+	// HTTP 服务
+	h := config.HTTP
+	server := &http.Server{
+		Addr:              h.Listen,
+		Handler:           corsMiddleware(newAPIMux()),
+		ReadHeaderTimeout: time.Duration(h.ReadHeaderTimeoutSec) * time.Second,
+		ReadTimeout:       time.Duration(h.ReadTimeoutSec) * time.Second,
+		WriteTimeout:      time.Duration(h.WriteTimeoutSec) * time.Second,
+		IdleTimeout:       time.Duration(h.IdleTimeoutSec) * time.Second,
+	}
+	// Shutdown 不会等待 SSE 之类的长连接，主动结束订阅
+	server.RegisterOnShutdown(events.Close)
 	go func() {
-		// ✅ 首次立即执行
-		fmt.Printf("[runScan] 首次立即执行: %s\n", time.Now().Format("15:04:05"))
-		runScan(resultsChan)
-
-		// 计算下一次对齐时间
-		now := time.Now()
-		nextAligned := now.Truncate(time.Minute).Add(time.Minute)
-		delay := time.Until(nextAligned)
-		time.Sleep(delay)
-
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
-		for t := range ticker.C {
-			progressLogger.Printf("[runScan] 每1分钟触发: %s", t.Format("15:04:05"))
-
-			// 如果上一次还在跑，则跳过本次（非阻塞）
-			if !atomic.CompareAndSwapInt32(&runScanRunning, 0, 1) {
-				progressLogger.Println("上一次 runScan 未结束，跳过本次执行")
-				continue
-			}
-			// 异步执行 runScanOnce，结束时清理标记
-			go func(execTime time.Time) {
-				defer atomic.StoreInt32(&runScanRunning, 0)
-				runScan(resultsChan)
-			}(t)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP服务器启动失败", "addr", h.Listen, "err", err)
+			os.Exit(1)
 		}
 	}()
 
+	go runScheduler(rootCtx, resultsChan)
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 	<-done
-	fmt.Println("程序已退出")
+	slog.Info("收到退出信号，开始关闭")
+	stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.ShutdownTimeoutSec)*time.Second)
+	defer cancel()
+	// 再次收到信号时立即退出
+	go func() {
+		<-done
+		slog.Warn("再次收到退出信号，强制退出")
+		os.Exit(1)
+	}()
+	shutdown(ctx, server, router, resultsChan, consumerDone)
+	slog.Info("程序已退出")
+}
+
+// runScheduler 首次立即扫描，之后在每根 1 分钟 K 线收盘并等待 settle 后扫描一次，ctx 取消后退出。
+// 每次扫描限时 ScanTimeoutSec，保证在下一次触发前结束而不是被跳过
+func runScheduler(ctx context.Context, resultsChan chan types.Signal) {
+	s := config.Settle
+	barSettle = schedule.NewSettle(time.Duration(s.InitialMs)*time.Millisecond,
+		time.Duration(s.MinMs)*time.Millisecond, time.Duration(s.MaxMs)*time.Millisecond, !s.Fixed)
+
+	startScan := func(barClose time.Time) {
+		// 如果上一次还在跑，则跳过本次（非阻塞）
+		if !atomic.CompareAndSwapInt32(&runScanRunning, 0, 1) {
+			metrics.ScanSkipped.Inc()
+			slog.Warn("上一次扫描未结束，跳过本次执行")
+			return
+		}
+		// 异步执行 runScan，结束时清理标记
+		scanCtx, cancel := context.WithTimeout(ctx, time.Duration(config.ScanTimeoutSec)*time.Second)
+		scanWG.Add(1)
+		go func() {
+			defer scanWG.Done()
+			defer atomic.StoreInt32(&runScanRunning, 0)
+			defer cancel()
+			runScan(scanCtx, barClose, resultsChan)
+		}()
+	}
+
+	// ✅ 首次立即执行，上一根 K 线早已收盘，无需等待
+	slog.Info("首次扫描立即执行")
+	startScan(time.Time{})
+
+	for {
+		barClose := schedule.M1.NextClose(time.Now())
+		timer := time.NewTimer(time.Until(barClose.Add(barSettle.Delay())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			slog.Info("K线收盘触发扫描", "bar_close", barClose.Format("15:04"),
+				logging.KeyTimeframe, closedFrames(barClose), "settle", barSettle.Delay())
+			startScan(barClose)
+		case <-scanNowCh:
+			timer.Stop()
+			slog.Info("管理接口触发扫描")
+			startScan(time.Time{})
+		}
+	}
+}
+
+// closedFrames 该时刻收盘的周期，如 "1m,5m,15m"
+func closedFrames(barClose time.Time) string {
+	var names []string
+	for _, tf := range schedule.ClosedAt(barClose, schedule.Cascade) {
+		names = append(names, tf.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// awaitFreshBars 用一个池子探测接口是否已出现 barClose 之后的新 K 线，
+// 未出现时每秒重试直到 settle 上限；观测到的延迟用于调整下一次的等待时长
+func awaitFreshBars(ctx context.Context, token *types.TokenItem, barClose time.Time) {
+	options := map[string]string{
+		"aggregate": "1",
+		"limit":     "2",
+		"token":     "base",
+		"currency":  "usd",
+	}
+	deadline := barClose.Add(barSettle.Max())
+	for {
+		candles, err := utils.GetOHLCVByAPI(ctx, *token, config, options, types.TimeframeMinute)
+		if err == nil && len(candles) > 0 && candles[len(candles)-1].Timestamp >= barClose.Unix() {
+			barSettle.Observe(time.Since(barClose))
+			return
+		}
+		if time.Now().After(deadline) {
+			logging.From(ctx).Warn("收盘后仍未取到新K线，继续扫描", "bar_close", barClose.Format("15:04"), "waited", barSettle.Max())
+			barSettle.Observe(barSettle.Max())
+			return
+		}
+		if utils.SleepContext(ctx, time.Second) != nil {
+			return
+		}
+	}
 }
 
-func runScan(resultsChan chan types.TokenItem) {
-	//10秒获K
-	time.Sleep(10 * time.Second)
-	fmt.Println("开始执行 runScan...")
+// shutdown 依次关闭：HTTP 服务 → 等待扫描结束 → 分发剩余信号 → 停止跟踪、等待下单、保存模拟盘 → 排空 Telegram 队列 → 关闭审计日志。
+// 超过 ctx 期限的步骤直接放弃
+func shutdown(ctx context.Context, server *http.Server, router *notify.Router, resultsChan chan types.Signal, consumerDone <-chan struct{}) {
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("HTTP 服务关闭失败", "err", err)
+	}
+
+	if err := waitGroupContext(ctx, &scanWG); err != nil {
+		slog.Warn("等待扫描结束超时", "err", err)
+	} else {
+		// 扫描已全部结束，不会再有写入，关闭后等待剩余信号分发完
+		close(resultsChan)
+		select {
+		case <-consumerDone:
+		case <-ctx.Done():
+		}
+	}
+
+	if err := router.Wait(ctx); err != nil {
+		slog.Warn("等待通知分发超时", "err", err)
+	}
+	outcomeTracker.Stop()
+	if tradeExecutor != nil {
+		if err := tradeExecutor.Wait(ctx); err != nil {
+			slog.Warn("等待下单完成超时", "err", err)
+		}
+		stopExecution()
+	}
+	if paperEngine != nil {
+		paperEngine.Close()
+	}
+
+	// 排空 Telegram 出站队列
+	if err := telegram.CloseOutbox(ctx); err != nil {
+		slog.Warn("Telegram 队列未完全发送", "err", err)
+	}
+	closeAuditLog()
+}
+
+func waitGroupContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runScan 扫描一轮；barClose 为触发本轮的 1 分钟收盘时间，手动或首次扫描时为零值
+func runScan(ctx context.Context, barClose time.Time, resultsChan chan types.Signal) {
+	rec := scanRecord{ID: nextScanID(), Start: time.Now(), BarClose: barClose, Status: scanCompleted}
+	defer func() { recordScan(rec) }()
+	ctx = logging.With(ctx, logging.KeyScanID, rec.ID)
+	logger := logging.From(ctx)
+
+	if scannerPaused.Load() {
+		logger.Info("扫描已暂停，跳过本轮")
+		rec.Status = scanPaused
+		return
+	}
+	logger.Info("开始扫描")
 
 	var (
 		tokenList []*types.TokenItem
@@ -107,7 +400,7 @@ func runScan(resultsChan chan types.TokenItem) {
 	)
 
 	for i := 0; i < maxRetry; i++ {
-		tokenList, err = utils.FetchRankData(config.Url, config.Proxy)
+		tokenList, err = utils.FetchRankData(ctx, config.Url, config.Proxy, currentFilters())
 
 		if err == nil {
 			break
@@ -115,35 +408,59 @@ func runScan(resultsChan chan types.TokenItem) {
 
 		// 判断是否是 403 错误
 		if strings.Contains(err.Error(), "403") {
-			fmt.Printf("第 %d 次尝试获取失败 (403)，重试中...\n", i+1)
-			time.Sleep(2 * time.Second)
+			logger.Warn("获取榜单返回 403，重试中", "attempt", i+1)
+			metrics.Retries.Inc(metrics.ServiceAxiom)
+			if utils.SleepContext(ctx, 2*time.Second) != nil {
+				rec.Status = interruptedStatus(ctx)
+				return
+			}
 			continue
+		} else if ctx.Err() != nil {
+			rec.Status = interruptedStatus(ctx)
+			return
 		} else {
 			// 其他错误直接退出
-			fmt.Printf("获取列表失败: %v\n", err)
+			logger.Error("获取榜单失败", "err", err)
+			rec.Status, rec.Error = scanFailed, err.Error()
 			return
 		}
 	}
 
 	if err != nil {
-		fmt.Printf("多次尝试后仍获取失败: %v\n", err)
+		logger.Error("多次尝试后仍获取榜单失败", "err", err)
+		rec.Status, rec.Error = scanFailed, err.Error()
 		return
 	}
-	var wg sync.WaitGroup
+	tokenList = mergeWatchlist(tokenList)
+	rec.Discovered = len(tokenList)
+
+	// 确认刚收盘的 K 线已可取，再开始分析
+	if !barClose.IsZero() && len(tokenList) > 0 {
+		awaitFreshBars(ctx, tokenList[0], barClose)
+	}
+
+	// 清理一天未出现的代币的持币记录
+	if holderEnricher != nil {
+		holderEnricher.Forget(24*time.Hour, time.Now())
+	}
+
+	var (
+		wg      sync.WaitGroup
+		signals atomic.Int32
+	)
 	sem := make(chan struct{}, 10) // 限制最大并发数
 
 	for _, token := range tokenList {
-		symbol := token.Symbol
-		banBool := false
-		for _, s := range banSymbols {
-			if symbol == s {
-				banBool = true
-				break
-			}
+		// 退出或超时后不再启动新的分析，等待已启动的结束
+		if ctx.Err() != nil {
+			break
 		}
-		if banBool {
+		symbol := token.Symbol
+		if isBanned(token) {
+			rec.Banned++
 			continue
 		}
+		rec.Evaluated++
 
 		// 确保 tokenDataMap 里有对应结构（初始化一次）
 		tokenDataMutex.Lock()
@@ -163,43 +480,122 @@ func runScan(resultsChan chan types.TokenItem) {
 		tokenDataMutex.Unlock()
 
 		// 启动并发分析
+		tokenCtx := logging.With(ctx, logging.KeySymbol, symbol, logging.KeyAddress, token.Address)
 		wg.Add(1)
-		go func(symbol string, data *types.TokenData) {
+		go func(tokenCtx context.Context, data *types.TokenData) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
-			utils.AnaylySymbol(data, config, resultsChan, config.BotToken, config.ChatID)
-		}(symbol, data)
+
+			tokenCtx, cancel := context.WithTimeout(tokenCtx, time.Duration(config.TokenTimeoutSec)*time.Second)
+			defer cancel()
+			enrichHolders(tokenCtx, data)
+			if utils.AnaylySymbol(tokenCtx, data, config, resultsChan) {
+				signals.Add(1)
+			}
+			if config.EnableShort && utils.AnaylySymbolShort(tokenCtx, data, config, resultsChan) {
+				signals.Add(1)
+			}
+		}(tokenCtx, data)
+	}
+
+	// 近期触发过或模拟盘持有的代币评估离场，与买入分析共用并发限制
+	var exits atomic.Int32
+	if !config.Exit.Disabled {
+		for _, origin := range exitCandidates(time.Now()) {
+			if ctx.Err() != nil {
+				break
+			}
+			exitCtx := logging.With(ctx, logging.KeySymbol, origin.Symbol, logging.KeyAddress, origin.Address)
+			wg.Add(1)
+			go func(exitCtx context.Context, origin types.Signal) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+
+				exitCtx, cancel := context.WithTimeout(exitCtx, time.Duration(config.TokenTimeoutSec)*time.Second)
+				defer cancel()
+				if utils.AnaylyExit(exitCtx, origin, config, resultsChan) {
+					markExit(origin.Address, time.Now())
+					exits.Add(1)
+				}
+			}(exitCtx, origin)
+		}
 	}
 
 	wg.Wait()
+	rec.Signals = int(signals.Load())
+	rec.Exits = int(exits.Load())
+	if ctx.Err() != nil {
+		rec.Status = interruptedStatus(ctx)
+	}
 }
 
-func latestMessagesHandler(w http.ResponseWriter, r *http.Request) {
-	// 参数limit，默认25
-	limit := 25
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if v, err := strconv.Atoi(l); err == nil && v > 0 {
-			limit = v
-		}
+// annotatePerp 代币已上线币安永续时在告警中附加合约、资金费率与持仓量；行情获取失败时只附合约名
+func annotatePerp(ctx context.Context, sig *types.Signal) {
+	if perpLister == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	listing, err := perpLister.Listing(ctx, sig.Address, sig.Symbol)
+	if err != nil {
+		slog.Warn("获取合约行情失败", logging.KeySymbol, sig.Symbol, logging.KeyAddress, sig.Address, "err", err)
+	}
+	if listing == nil {
+		return
 	}
+	sig.Perp = listing
+	sig.Text += "\n" + perp.Format(listing)
+}
 
-	msgs := telegram.GetLatestMessages(limit)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(msgs)
+// dispatchExit 分发 XSELL：以回复形式引用原信号，不参与结果跟踪与模拟盘开仓
+func dispatchExit(router *notify.Router, sig types.Signal) {
+	metrics.ExitSignals.Inc(sig.Strategy)
+	slog.Info("离场信号触发", "id", sig.ID, "reply_to", sig.ReplyTo, "strategy", sig.Strategy,
+		logging.KeySymbol, sig.Symbol, logging.KeyAddress, sig.Address, "price", sig.Price)
+	recordSignal(sig)
+	events.Publish(stream.EventSignal, sig.Chain, sig)
+	router.Dispatch(notify.Alert{
+		ID:       sig.ID,
+		ReplyTo:  sig.ReplyTo,
+		Strategy: sig.Strategy,
+		Chain:    sig.Chain,
+		Tier:     notify.TierExit,
+		Symbol:   sig.Symbol,
+		Address:  sig.Address,
+		Text:     sig.Text,
+		Time:     sig.Time,
+	})
 }
 
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+// interruptedStatus 区分退出取消与超过扫描期限
+func interruptedStatus(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return scanTimeout
+	}
+	return scanCanceled
+}
 
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+// nextSignalID 生成进程内唯一的信号 ID
+func nextSignalID() string {
+	return fmt.Sprintf("%s-%d", time.Now().Format("20060102150405"), signalSeq.Add(1))
+}
 
-		next.ServeHTTP(w, r)
-	})
+// signalPrice 跟踪器采样价格：取该池子最新 1 分钟收盘价
+func signalPrice(ctx context.Context, sig types.Signal) (float64, error) {
+	return utils.GetLatestPrice(ctx, types.TokenItem{
+		Chain:       sig.Chain,
+		Symbol:      sig.Symbol,
+		Address:     sig.Address,
+		PoolAddress: sig.PoolAddress,
+	}, config)
 }
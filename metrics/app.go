@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 上游服务名
+const (
+	ServiceGecko    = "geckoterminal"
+	ServiceAxiom    = "axiom"
+	ServiceTelegram = "telegram"
+	ServiceBanList  = "banlist"
+	ServiceBinance  = "binance"
+	ServiceSolana   = "solana_rpc"
+)
+
+var (
+	ScanDuration = NewHistogram("dex_scan_duration_seconds", "Duration of a scan cycle.",
+		[]float64{1, 2, 5, 10, 20, 30, 45, 60, 90}, "status")
+	ScanTokens = NewGauge("dex_scan_tokens", "Tokens in the last scan cycle by kind (discovered, filtered, banned, evaluated).",
+		"kind")
+	ScanTokensTotal = NewCounter("dex_scan_tokens_total", "Tokens seen across scan cycles by kind.", "kind")
+	ScanSkipped     = NewCounter("dex_scan_skipped_total", "Scan cycles skipped because the previous one was still running.")
+
+	StageEvaluations = NewCounter("dex_stage_evaluations_total", "Cascade stage evaluations by timeframe and result.",
+		"timeframe", "result")
+	SignalsFired = NewCounter("dex_signals_total", "Signals fired by strategy.", "strategy")
+	ExitSignals  = NewCounter("dex_exit_signals_total", "Exit (XSELL) follow-ups fired by strategy.", "strategy")
+
+	UpstreamRequests = NewCounter("dex_upstream_requests_total", "Upstream HTTP requests by service and status.",
+		"service", "status")
+	UpstreamLatency = NewHistogram("dex_upstream_request_duration_seconds", "Upstream HTTP request latency by service and status.",
+		[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}, "service", "status")
+	Retries = NewCounter("dex_retries_total", "Retried upstream requests by service.", "service")
+
+	Orders = NewCounter("dex_orders_total", "Orders sent by the trade executor by venue, side and result.",
+		"venue", "side", "result")
+
+	PanicsRecovered = NewCounter("dex_panics_recovered_total", "Recovered panics by component.", "component")
+
+	UpstreamLastSuccess = NewGauge("dex_upstream_last_success_timestamp_seconds",
+		"Unix time of the last successful (2xx) upstream request by service.", "service")
+)
+
+// lastSuccess 各上游最近一次成功请求的时间，供 /readyz 使用
+var lastSuccess = struct {
+	sync.Mutex
+	at map[string]time.Time
+}{at: make(map[string]time.Time)}
+
+// LastSuccess 返回该上游最近一次成功请求的时间，从未成功时为零值
+func LastSuccess(service string) time.Time {
+	lastSuccess.Lock()
+	defer lastSuccess.Unlock()
+	return lastSuccess.at[service]
+}
+
+// ObserveRequest 记录一次上游请求；err 非空时 status 记为 "error"
+func ObserveRequest(service string, start time.Time, resp *http.Response, err error) {
+	status := "error"
+	if err == nil && resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	UpstreamRequests.Inc(service, status)
+	UpstreamLatency.Observe(time.Since(start).Seconds(), service, status)
+	if err == nil && resp != nil && resp.StatusCode/100 == 2 {
+		now := time.Now()
+		lastSuccess.Lock()
+		lastSuccess.at[service] = now
+		lastSuccess.Unlock()
+		UpstreamLastSuccess.Set(float64(now.Unix()), service)
+	}
+}
+
+// ObserveScanTokens 记录一轮扫描中某类代币数量
+func ObserveScanTokens(kind string, n int) {
+	ScanTokens.Set(float64(n), kind)
+	ScanTokensTotal.Add(float64(n), kind)
+}
@@ -0,0 +1,249 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// collector 以 Prometheus 文本格式输出自身
+type collector interface {
+	write(w io.Writer)
+}
+
+// Registry 指标集合
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// Default 全局指标集合，/metrics 输出它
+var Default = &Registry{}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText 按 text exposition format 0.0.4 输出全部指标
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.Unlock()
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler /metrics 接口
+func Handler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	Default.WriteText(w)
+}
+
+// series 带标签的一组值，按标签值分组
+type series[V any] struct {
+	name   string
+	help   string
+	typ    string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*V
+	keys   map[string][]string // key -> 标签值
+}
+
+func newSeries[V any](name, help, typ string, labels []string) *series[V] {
+	return &series[V]{name: name, help: help, typ: typ, labels: labels,
+		values: make(map[string]*V), keys: make(map[string][]string)}
+}
+
+// get 返回标签值对应的值，调用方需持有 mu
+func (s *series[V]) get(lvs []string, init func() *V) *V {
+	if len(lvs) != len(s.labels) {
+		panic(fmt.Sprintf("metrics: %s 需要 %d 个标签值，实际 %d", s.name, len(s.labels), len(lvs)))
+	}
+	key := strings.Join(lvs, "\xff")
+	v, ok := s.values[key]
+	if !ok {
+		v = init()
+		s.values[key] = v
+		s.keys[key] = slices.Clone(lvs)
+	}
+	return v
+}
+
+// each 按标签值排序遍历，输出稳定
+func (s *series[V]) each(fn func(lvs []string, v *V)) {
+	keys := make([]string, 0, len(s.values))
+	for k := range s.values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		fn(s.keys[k], s.values[k])
+	}
+}
+
+func (s *series[V]) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", s.name, s.help, s.name, s.typ)
+}
+
+// Counter 只增计数器
+type Counter struct{ s *series[float64] }
+
+// NewCounter 创建并注册计数器
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{newSeries[float64](name, help, "counter", labels)}
+	if len(labels) == 0 {
+		c.s.get(nil, func() *float64 { return new(float64) }) // 无标签时从 0 开始输出
+	}
+	Default.register(c)
+	return c
+}
+
+// Inc 计数加 1
+func (c *Counter) Inc(lvs ...string) { c.Add(1, lvs...) }
+
+// Add 计数加 v，v 不能为负
+func (c *Counter) Add(v float64, lvs ...string) {
+	if v < 0 {
+		return
+	}
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	*c.s.get(lvs, func() *float64 { return new(float64) }) += v
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	c.s.header(w)
+	c.s.each(func(lvs []string, v *float64) {
+		fmt.Fprintf(w, "%s%s %s\n", c.s.name, labelText(c.s.labels, lvs, "", ""), formatFloat(*v))
+	})
+}
+
+// Gauge 可增可减的瞬时值
+type Gauge struct{ s *series[float64] }
+
+// NewGauge 创建并注册 gauge
+func NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{newSeries[float64](name, help, "gauge", labels)}
+	if len(labels) == 0 {
+		g.s.get(nil, func() *float64 { return new(float64) }) // 无标签时从 0 开始输出
+	}
+	Default.register(g)
+	return g
+}
+
+// Set 设置当前值
+func (g *Gauge) Set(v float64, lvs ...string) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	*g.s.get(lvs, func() *float64 { return new(float64) }) = v
+}
+
+func (g *Gauge) write(w io.Writer) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	g.s.header(w)
+	g.s.each(func(lvs []string, v *float64) {
+		fmt.Fprintf(w, "%s%s %s\n", g.s.name, labelText(g.s.labels, lvs, "", ""), formatFloat(*v))
+	})
+}
+
+// Histogram 累积分桶直方图
+type Histogram struct {
+	s       *series[histValue]
+	buckets []float64
+}
+
+type histValue struct {
+	counts []uint64 // 与 buckets 一一对应，非累积
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram 创建并注册直方图，buckets 为升序上界（不含 +Inf）
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{newSeries[histValue](name, help, "histogram", labels), slices.Clone(buckets)}
+	slices.Sort(h.buckets)
+	Default.register(h)
+	return h
+}
+
+// Observe 记录一次观测值
+func (h *Histogram) Observe(v float64, lvs ...string) {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	hv := h.s.get(lvs, func() *histValue { return &histValue{counts: make([]uint64, len(h.buckets))} })
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		hv.counts[i]++
+	}
+	hv.sum += v
+	hv.count++
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.header(w)
+	h.s.each(func(lvs []string, hv *histValue) {
+		var cum uint64
+		for i, le := range h.buckets {
+			cum += hv.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.s.name, labelText(h.s.labels, lvs, "le", formatFloat(le)), cum)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.s.name, labelText(h.s.labels, lvs, "le", "+Inf"), hv.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.s.name, labelText(h.s.labels, lvs, "", ""), formatFloat(hv.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.s.name, labelText(h.s.labels, lvs, "", ""), hv.count)
+	})
+}
+
+// labelText 形如 {a="x",b="y"}，extra 非空时追加一个标签（直方图的 le）
+func labelText(names, values []string, extra, extraValue string) string {
+	if len(names) == 0 && extra == "" {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `%s="%s"`, n, escapeLabel(values[i]))
+	}
+	if extra != "" {
+		if len(names) > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `%s="%s"`, extra, extraValue)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
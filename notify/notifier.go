@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// 告警级别
+const (
+	TierSignal  = "signal"  // 常规信号
+	TierFirst   = "first"   // 首次警报
+	TierOutcome = "outcome" // 信号结果跟踪（回复原信号）
+	TierExit    = "exit"    // 离场信号（回复原信号）
+)
+
+// Alert 发往各通知渠道的告警
+type Alert struct {
+	ID       string    `json:"id,omitempty"`       // 信号 ID
+	ReplyTo  string    `json:"reply_to,omitempty"` // 跟进消息所引用的原信号 ID
+	Strategy string    `json:"strategy"`
+	Chain    string    `json:"chain"`
+	Tier     string    `json:"tier"`
+	Symbol   string    `json:"symbol"`
+	Address  string    `json:"address"`
+	Text     string    `json:"text"` // Markdown 文本
+	Time     time.Time `json:"time"`
+	Photo    []byte    `json:"-"` // 可选的 PNG 图表，不支持图片的渠道忽略
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, a Alert) error
+}
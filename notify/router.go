@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/telegram"
+	"onchain-energe-SRSI/types"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 内置渠道名称
+const (
+	ChannelTelegram      = "telegram"       // 主 bot，接收常规信号
+	ChannelTelegramAlert = "telegram_alert" // 首次警报 bot
+)
+
+// Router 按策略、链与告警级别把告警分发到渠道
+type Router struct {
+	notifiers map[string]Notifier
+	routes    []types.RouteConfig
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	closed   bool // Wait 开始后不再接受新的告警，避免 inflight.Add 与 Wait 并发
+	inflight sync.WaitGroup
+}
+
+// NewRouter 根据配置创建渠道与路由；未配置 routes 时保持原行为：
+// 常规信号与结果跟进发往主 bot，首次警报发往 alert bot
+func NewRouter(cfg *types.Config) (*Router, error) {
+	alertToken, alertChat := telegram.AlertBot()
+	r := &Router{
+		notifiers: map[string]Notifier{
+			ChannelTelegram:      &Telegram{ChannelName: ChannelTelegram, BotToken: cfg.BotToken, ChatID: cfg.ChatID},
+			ChannelTelegramAlert: &Telegram{ChannelName: ChannelTelegramAlert, BotToken: alertToken, ChatID: alertChat},
+		},
+		routes:  cfg.Routes,
+		timeout: 30 * time.Second,
+	}
+
+	for _, nc := range cfg.Notifiers {
+		n, err := newNotifier(nc)
+		if err != nil {
+			return nil, err
+		}
+		r.notifiers[nc.Name] = n
+	}
+
+	if len(r.routes) == 0 {
+		r.routes = []types.RouteConfig{
+			{Channels: []string{ChannelTelegram}, Tiers: []string{TierSignal, TierOutcome, TierExit}},
+			{Channels: []string{ChannelTelegramAlert}, Tiers: []string{TierFirst}},
+		}
+	}
+	for _, rc := range r.routes {
+		for _, ch := range rc.Channels {
+			if _, ok := r.notifiers[ch]; !ok {
+				return nil, fmt.Errorf("路由引用了未定义的渠道: %s", ch)
+			}
+		}
+	}
+	return r, nil
+}
+
+func newNotifier(nc types.NotifierConfig) (Notifier, error) {
+	if nc.Name == "" {
+		return nil, fmt.Errorf("通知渠道缺少 name (type=%s)", nc.Type)
+	}
+	switch nc.Type {
+	case "telegram":
+		return &Telegram{ChannelName: nc.Name, BotToken: nc.BotToken, ChatID: nc.ChatID}, nil
+	case "discord":
+		return &Discord{ChannelName: nc.Name, WebhookURL: nc.URL}, nil
+	case "slack":
+		return &Slack{ChannelName: nc.Name, WebhookURL: nc.URL}, nil
+	case "webhook":
+		return &Webhook{ChannelName: nc.Name, URL: nc.URL, Secret: nc.Secret}, nil
+	case "stdout":
+		return &Stdout{ChannelName: nc.Name}, nil
+	default:
+		return nil, fmt.Errorf("未知的通知渠道类型 %q (%s)", nc.Type, nc.Name)
+	}
+}
+
+// Channels 返回告警命中的渠道（去重，按规则顺序）
+func (r *Router) Channels(a Alert) []string {
+	var res []string
+	for _, rc := range r.routes {
+		if !matches(rc.Strategies, a.Strategy) || !matches(rc.Chains, a.Chain) || !matches(rc.Tiers, a.Tier) {
+			continue
+		}
+		for _, ch := range rc.Channels {
+			if !slices.Contains(res, ch) {
+				res = append(res, ch)
+			}
+		}
+	}
+	return res
+}
+
+// Dispatch 异步发往所有命中的渠道，不阻塞调用方；Wait 开始后丢弃
+func (r *Router) Dispatch(a Alert) {
+	if a.Time.IsZero() {
+		a.Time = time.Now()
+	}
+	channels := r.Channels(a)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		logging.Component("notify").Warn("正在关闭，丢弃告警", "tier", a.Tier,
+			logging.KeySymbol, a.Symbol, logging.KeyAddress, a.Address)
+		return
+	}
+	for _, ch := range channels {
+		n := r.notifiers[ch]
+		r.inflight.Add(1)
+		go func() {
+			defer r.inflight.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+			defer cancel()
+			if err := n.Notify(ctx, a); err != nil {
+				logging.Component("notify").Warn("发送告警失败", "channel", n.Name(), "tier", a.Tier,
+					logging.KeySymbol, a.Symbol, logging.KeyAddress, a.Address, "err", err)
+			}
+		}()
+	}
+}
+
+// Wait 停止接受新告警并等待已分发的告警发送完成（Telegram 渠道为入队完成）
+func (r *Router) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// matches 规则为空表示不限
+func matches(allowed []string, v string) bool {
+	return len(allowed) == 0 || slices.Contains(allowed, v) || slices.Contains(allowed, "*")
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"onchain-energe-SRSI/types"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChannels(t *testing.T) {
+	r := &Router{routes: []types.RouteConfig{
+		{Channels: []string{"main"}, Tiers: []string{TierSignal, TierExit}},
+		{Channels: []string{"alert"}, Tiers: []string{TierFirst}},
+		{Channels: []string{"short", "main"}, Strategies: []string{"cascade_short"}},
+		{Channels: []string{"sol"}, Chains: []string{"solana"}, Tiers: []string{"*"}},
+		{Channels: []string{"all"}, Strategies: []string{"*"}, Chains: []string{"*"}, Tiers: []string{"*"}},
+	}}
+	tests := []struct {
+		name string
+		a    Alert
+		want []string
+	}{
+		{"按级别", Alert{Strategy: "cascade", Chain: "bsc", Tier: TierSignal}, []string{"main", "all"}},
+		{"首次警报", Alert{Strategy: "cascade", Chain: "bsc", Tier: TierFirst}, []string{"alert", "all"}},
+		{"按策略，去重保持规则顺序", Alert{Strategy: "cascade_short", Chain: "bsc", Tier: TierSignal}, []string{"main", "short", "all"}},
+		{"按链，级别通配", Alert{Strategy: "cascade", Chain: "solana", Tier: TierOutcome}, []string{"sol", "all"}},
+		{"仅通配规则命中", Alert{Strategy: "cascade", Chain: "bsc", Tier: TierOutcome}, []string{"all"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Channels(tt.a); !slices.Equal(got, tt.want) {
+				t.Errorf("Channels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type countNotifier struct{ n atomic.Int32 }
+
+func (c *countNotifier) Name() string { return "count" }
+
+func (c *countNotifier) Notify(context.Context, Alert) error {
+	c.n.Add(1)
+	return nil
+}
+
+func TestDispatchAfterWait(t *testing.T) {
+	c := &countNotifier{}
+	r := &Router{
+		notifiers: map[string]Notifier{"count": c},
+		routes:    []types.RouteConfig{{Channels: []string{"count"}}},
+		timeout:   time.Second,
+	}
+	r.Dispatch(Alert{Tier: TierSignal})
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r.Dispatch(Alert{Tier: TierSignal})
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.n.Load(); got != 1 {
+		t.Errorf("Notify 调用 %d 次，Wait 之后的告警应被丢弃", got)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// 参考值: printf '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	got := Sign("secret", "1700000000", []byte(`{"a":1}`))
+	if want := "49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"; got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookSignatureHeaders(t *testing.T) {
+	var ts, sig string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ts = req.Header.Get("X-Signature-Timestamp")
+		sig = req.Header.Get("X-Signature")
+		body, _ = io.ReadAll(req.Body)
+	}))
+	defer srv.Close()
+
+	h := &Webhook{ChannelName: "hook", URL: srv.URL, Secret: "secret", Client: srv.Client()}
+	if err := h.Notify(context.Background(), Alert{Tier: TierSignal, Symbol: "ABC"}); err != nil {
+		t.Fatal(err)
+	}
+	if ts == "" || len(body) == 0 {
+		t.Fatalf("缺少签名时间戳或请求体: ts=%q body=%q", ts, body)
+	}
+	if want := "sha256=" + Sign("secret", ts, body); sig != want {
+		t.Errorf("X-Signature = %q, want %q", sig, want)
+	}
+
+	// 未配置 Secret 时不附带签名
+	h.Secret = ""
+	ts, sig = "", ""
+	if err := h.Notify(context.Background(), Alert{Tier: TierSignal}); err != nil {
+		t.Fatal(err)
+	}
+	if ts != "" || sig != "" {
+		t.Errorf("未配置 Secret 不应签名: ts=%q sig=%q", ts, sig)
+	}
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Stdout 直接打印，便于本地调试
+type Stdout struct {
+	ChannelName string
+	W           io.Writer // 为空时使用 os.Stdout
+}
+
+func (s *Stdout) Name() string { return s.ChannelName }
+
+func (s *Stdout) Notify(_ context.Context, a Alert) error {
+	w := s.W
+	if w == nil {
+		w = os.Stdout
+	}
+	_, err := fmt.Fprintf(w, "[%s][%s][%s] %s\n", a.Tier, a.Strategy, a.Chain, a.Text)
+	return err
+}
@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"onchain-energe-SRSI/telegram"
+	"sync"
+	"time"
+)
+
+// Telegram 通过 telegram outbox 异步发送，Notify 只负责入队。
+// 记录信号 ID 与 message_id 的对应关系，跟进消息以回复形式发送
+type Telegram struct {
+	ChannelName string
+	BotToken    string
+	ChatID      string
+
+	mu   sync.Mutex
+	sent map[string]sentMessage // 信号 ID -> 已发送消息
+}
+
+type sentMessage struct {
+	messageID int
+	at        time.Time
+}
+
+// sentRetention message_id 保留时长，需覆盖最长的跟进检查点
+const sentRetention = 24 * time.Hour
+
+func (t *Telegram) Name() string { return t.ChannelName }
+
+func (t *Telegram) Notify(_ context.Context, a Alert) error {
+	m := telegram.OutboundMessage{
+		BotToken:  t.BotToken,
+		ChatID:    t.ChatID,
+		Text:      a.Text,
+		ParseMode: "Markdown",
+		Photo:     a.Photo,
+		Priority:  telegram.PriorityNormal,
+		ReplyTo:   t.messageID(a.ReplyTo),
+		// 常规信号写入 savedMessages，参与首次警报判定
+		Record: a.Tier == TierSignal,
+	}
+	if a.Tier == TierFirst {
+		m.Priority = telegram.PriorityHigh
+		m.ParseMode = ""
+	}
+	if a.ID != "" {
+		m.OnSent = func(messageID int) { t.remember(a.ID, messageID) }
+	}
+	return telegram.Enqueue(m)
+}
+
+func (t *Telegram) remember(id string, messageID int) {
+	if messageID == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sent == nil {
+		t.sent = make(map[string]sentMessage)
+	}
+	now := time.Now()
+	for k, v := range t.sent {
+		if now.Sub(v.at) > sentRetention {
+			delete(t.sent, k)
+		}
+	}
+	t.sent[id] = sentMessage{messageID: messageID, at: now}
+}
+
+func (t *Telegram) messageID(id string) int {
+	if id == "" {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sent[id].messageID
+}
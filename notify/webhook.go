@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Discord webhook，带图时以附件上传
+type Discord struct {
+	ChannelName string
+	WebhookURL  string
+	Client      *http.Client
+}
+
+func (d *Discord) Name() string { return d.ChannelName }
+
+func (d *Discord) Notify(ctx context.Context, a Alert) error {
+	payload, err := json.Marshal(map[string]string{"content": a.Text})
+	if err != nil {
+		return err
+	}
+	if a.Photo == nil {
+		return postJSON(ctx, d.Client, d.WebhookURL, payload, nil)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+	part, err := w.CreateFormFile("files[0]", "chart.png")
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(a.Photo); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return post(ctx, d.Client, d.WebhookURL, w.FormDataContentType(), body.Bytes(), nil)
+}
+
+// Slack incoming webhook，仅文字
+type Slack struct {
+	ChannelName string
+	WebhookURL  string
+	Client      *http.Client
+}
+
+func (s *Slack) Name() string { return s.ChannelName }
+
+func (s *Slack) Notify(ctx context.Context, a Alert) error {
+	payload, err := json.Marshal(map[string]string{"text": a.Text})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.Client, s.WebhookURL, payload, nil)
+}
+
+// Webhook 通用 JSON 回调，配置 Secret 时附带 HMAC-SHA256 签名：
+// X-Signature-Timestamp: unix 秒；X-Signature: sha256=hex(HMAC(secret, timestamp + "." + body))
+type Webhook struct {
+	ChannelName string
+	URL         string
+	Secret      string
+	Client      *http.Client
+}
+
+func (h *Webhook) Name() string { return h.ChannelName }
+
+func (h *Webhook) Notify(ctx context.Context, a Alert) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{}
+	if h.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		headers["X-Signature-Timestamp"] = ts
+		headers["X-Signature"] = "sha256=" + Sign(h.Secret, ts, payload)
+	}
+	return postJSON(ctx, h.Client, h.URL, payload, headers)
+}
+
+// Sign 计算 webhook 签名，接收方可用相同方法校验
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	return post(ctx, client, url, "application/json", body, headers)
+}
+
+func post(ctx context.Context, client *http.Client, url, contentType string, body []byte, headers map[string]string) error {
+	if client == nil {
+		client = defaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("非 2xx 返回 %s: %s", resp.Status, msg)
+	}
+	return nil
+}
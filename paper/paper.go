@@ -0,0 +1,448 @@
+package paper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/types"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 平仓原因
+const (
+	ExitTakeProfit = "take_profit"
+	ExitStopLoss   = "stop_loss"
+	ExitTrailing   = "trailing_stop"
+	ExitMA60       = "ma60_break" // 5m 收盘跌破 MA60（空头为升破）
+	ExitMaxHold    = "max_hold"
+)
+
+// maxEquityPoints 权益曲线保留的点数
+const maxEquityPoints = 10000
+
+// Config 模拟盘参数。金额均以 SOL 计价（与 Axiom 流动性单位一致），忽略 SOL/USD 波动；
+// 空头以开仓金额为保证金，不计杠杆与资金费
+type Config struct {
+	StartingBalance float64       // 初始资金
+	PositionSize    float64       // 每次开仓金额
+	MaxPositions    int           // 同时持仓上限
+	FeePct          float64       // 单边手续费（%）
+	MinSlippagePct  float64       // 最低滑点（%），流动性冲击低于此值时使用
+	TakeProfitPct   float64       // 止盈（%），0 表示不启用
+	StopLossPct     float64       // 止损（%），0 表示不启用
+	TrailingStopPct float64       // 自最有利价格回撤（%），仅在浮盈后生效，0 表示不启用
+	ExitBelowMA60   bool          // 5m 收盘跌破 MA60 时平仓，空头为升破
+	MaxHold         time.Duration // 超过后平仓，0 表示不启用
+	CheckInterval   time.Duration
+}
+
+// Quote 持仓复查时的行情
+type Quote struct {
+	Price   float64 // 最新价
+	Close5m float64 // 最近一根已收盘 5m K 线收盘价
+	MA60    float64 // 5m MA60，0 表示数据不足
+}
+
+// QuoteFunc 查询持仓代币的行情
+type QuoteFunc func(ctx context.Context, pos Position) (Quote, error)
+
+// Position 持仓
+type Position struct {
+	ID          string    `json:"id"` // 开仓信号 ID
+	Strategy    string    `json:"strategy"`
+	Chain       string    `json:"chain"`
+	Symbol      string    `json:"symbol"`
+	Address     string    `json:"address"`
+	PoolAddress string    `json:"pool_address"`
+	Short       bool      `json:"short,omitempty"`
+	Liquidity   float64   `json:"liquidity"` // 开仓时的流动性（SOL）
+	SignalPrice float64   `json:"signal_price"`
+	EntryPrice  float64   `json:"entry_price"` // 含滑点的成交均价
+	Quantity    float64   `json:"quantity"`
+	Cost        float64   `json:"cost"` // 投入金额（含手续费）
+	Fees        float64   `json:"fees"`
+	PeakPrice   float64   `json:"peak_price"` // 最有利价格：多头为最高价，空头为最低价
+	LastPrice   float64   `json:"last_price"`
+	OpenedAt    time.Time `json:"opened_at"`
+}
+
+// Trade 已平仓交易
+type Trade struct {
+	Position
+	ExitPrice float64   `json:"exit_price"` // 含滑点的成交均价
+	Proceeds  float64   `json:"proceeds"`   // 扣除手续费后的回款
+	PnL       float64   `json:"pnl"`
+	PnLPct    float64   `json:"pnl_pct"`
+	Reason    string    `json:"reason"`
+	ClosedAt  time.Time `json:"closed_at"`
+}
+
+// EquityPoint 权益曲线上的一点
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// ledger 持久化到文件的账本
+type ledger struct {
+	Cash      float64       `json:"cash"`
+	Positions []*Position   `json:"positions"`
+	Trades    []Trade       `json:"trades"`
+	Equity    []EquityPoint `json:"equity"`
+}
+
+// Stats 汇总统计
+type Stats struct {
+	Cash           float64 `json:"cash"`
+	Equity         float64 `json:"equity"`
+	ReturnPct      float64 `json:"return_pct"`
+	OpenPositions  int     `json:"open_positions"`
+	Trades         int     `json:"trades"`
+	Wins           int     `json:"wins"`
+	WinRate        float64 `json:"win_rate"`
+	RealizedPnL    float64 `json:"realized_pnl"`
+	AvgPnLPct      float64 `json:"avg_pnl_pct"`
+	ProfitFactor   float64 `json:"profit_factor"` // 盈利总额 / 亏损总额，无亏损时为 0
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	TotalFees      float64 `json:"total_fees"`
+}
+
+// Engine 根据信号开模拟仓位并按规则平仓，账本保存在 path
+type Engine struct {
+	cfg   Config
+	quote QuoteFunc
+	path  string
+
+	mu sync.Mutex
+	l  ledger
+}
+
+// New 创建模拟盘，path 存在时从中恢复账本
+func New(cfg Config, quote QuoteFunc, path string) (*Engine, error) {
+	e := &Engine{cfg: cfg, quote: quote, path: path, l: ledger{Cash: cfg.StartingBalance}}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("读取模拟盘账本失败: %w", err)
+	default:
+		if err := json.Unmarshal(data, &e.l); err != nil {
+			return nil, fmt.Errorf("解析模拟盘账本失败: %w", err)
+		}
+	}
+	return e, nil
+}
+
+// Open 按信号开仓（空头信号卖出开空），滑点按信号中的流动性估算；同一代币已有持仓、持仓已满或资金不足时跳过
+func (e *Engine) Open(sig types.Signal) {
+	if sig.Price <= 0 {
+		return
+	}
+	logger := logging.Component("paper").With(logging.KeySymbol, sig.Symbol, logging.KeyAddress, sig.Address)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, p := range e.l.Positions {
+		if p.Address == sig.Address {
+			return
+		}
+	}
+	if e.cfg.MaxPositions > 0 && len(e.l.Positions) >= e.cfg.MaxPositions {
+		logger.Info("持仓已满，跳过开仓", "positions", len(e.l.Positions))
+		return
+	}
+	size := e.cfg.PositionSize
+	if size <= 0 || e.l.Cash < size {
+		logger.Info("资金不足，跳过开仓", "cash", e.l.Cash)
+		return
+	}
+
+	fee := size * e.cfg.FeePct / 100
+	slip := e.slippage(size-fee, sig.Liquidity)
+	fill := sig.Price * (1 + slip)
+	if sig.Short {
+		fill = sig.Price * (1 - slip)
+	}
+	pos := &Position{
+		ID:          sig.ID,
+		Strategy:    sig.Strategy,
+		Chain:       sig.Chain,
+		Symbol:      sig.Symbol,
+		Address:     sig.Address,
+		PoolAddress: sig.PoolAddress,
+		Short:       sig.Short,
+		Liquidity:   sig.Liquidity,
+		SignalPrice: sig.Price,
+		EntryPrice:  fill,
+		Quantity:    (size - fee) / fill,
+		Cost:        size,
+		Fees:        fee,
+		PeakPrice:   sig.Price,
+		LastPrice:   sig.Price,
+		OpenedAt:    time.Now(),
+	}
+	e.l.Cash -= size
+	e.l.Positions = append(e.l.Positions, pos)
+	e.recordEquity()
+	e.save()
+	logger.Info("模拟开仓", "short", sig.Short, "entry", fill, "size", size, "fee", fee)
+}
+
+// slippage 恒定乘积池的价格冲击：成交 amount 时均价偏离 amount/liquidity，不低于 MinSlippagePct
+func (e *Engine) slippage(amount, liquidity float64) float64 {
+	s := e.cfg.MinSlippagePct / 100
+	if liquidity > 0 {
+		s = max(s, amount/liquidity)
+	}
+	return s
+}
+
+// Run 定期复查持仓直到 ctx 取消
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Check(ctx)
+		}
+	}
+}
+
+// Check 复查全部持仓，满足任一平仓规则时按含滑点价格平仓
+func (e *Engine) Check(ctx context.Context) {
+	e.mu.Lock()
+	positions := make([]Position, len(e.l.Positions))
+	for i, p := range e.l.Positions {
+		positions[i] = *p
+	}
+	e.mu.Unlock()
+
+	quotes := make(map[string]Quote, len(positions))
+	for _, p := range positions {
+		q, err := e.quote(ctx, p)
+		if err != nil || q.Price <= 0 {
+			if ctx.Err() != nil {
+				return
+			}
+			logging.Component("paper").Warn("获取持仓行情失败", logging.KeySymbol, p.Symbol, logging.KeyAddress, p.Address, "err", err)
+			continue
+		}
+		quotes[p.ID] = q
+	}
+
+	now := time.Now()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var open []*Position
+	for _, p := range e.l.Positions {
+		q, ok := quotes[p.ID]
+		if !ok {
+			open = append(open, p)
+			continue
+		}
+		p.LastPrice = q.Price
+		if p.Short {
+			p.PeakPrice = min(p.PeakPrice, q.Price)
+		} else {
+			p.PeakPrice = max(p.PeakPrice, q.Price)
+		}
+		if reason := e.exitReason(p, q, now); reason != "" {
+			e.close(p, q.Price, reason)
+			continue
+		}
+		open = append(open, p)
+	}
+	e.l.Positions = open
+	e.recordEquity()
+	e.save()
+}
+
+// exitReason 按顺序检查止盈、止损、回撤止盈、MA60 与最长持有时间；空头方向取反
+func (e *Engine) exitReason(p *Position, q Quote, now time.Time) string {
+	change := (q.Price/p.EntryPrice - 1) * 100
+	retrace := (1 - q.Price/p.PeakPrice) * 100
+	inProfit := p.PeakPrice > p.EntryPrice
+	brokeMA := q.Close5m < q.MA60
+	if p.Short {
+		change = -change
+		retrace = (q.Price/p.PeakPrice - 1) * 100
+		inProfit = p.PeakPrice < p.EntryPrice
+		brokeMA = q.Close5m > q.MA60
+	}
+	switch {
+	case e.cfg.TakeProfitPct > 0 && change >= e.cfg.TakeProfitPct:
+		return ExitTakeProfit
+	case e.cfg.StopLossPct > 0 && change <= -e.cfg.StopLossPct:
+		return ExitStopLoss
+	case e.cfg.TrailingStopPct > 0 && inProfit && retrace >= e.cfg.TrailingStopPct:
+		return ExitTrailing
+	case e.cfg.ExitBelowMA60 && q.MA60 > 0 && q.Close5m > 0 && brokeMA:
+		return ExitMA60
+	case e.cfg.MaxHold > 0 && now.Sub(p.OpenedAt) >= e.cfg.MaxHold:
+		return ExitMaxHold
+	}
+	return ""
+}
+
+// value 按 price 估算持仓价值：多头为市值，空头为保证金加浮动盈亏
+func (p *Position) value(price float64) float64 {
+	if p.Short {
+		return p.Quantity * (2*p.EntryPrice - price)
+	}
+	return p.Quantity * price
+}
+
+// close 平仓并记入交易，调用方需持有 mu
+func (e *Engine) close(p *Position, price float64, reason string) {
+	slip := e.slippage(p.Quantity*price, p.Liquidity)
+	fill := price * (1 - slip)
+	if p.Short {
+		fill = price * (1 + slip)
+	}
+	fee := p.Quantity * fill * e.cfg.FeePct / 100
+	t := Trade{
+		Position:  *p,
+		ExitPrice: fill,
+		Proceeds:  p.value(fill) - fee,
+		Reason:    reason,
+		ClosedAt:  time.Now(),
+	}
+	t.Fees += fee
+	t.PnL = t.Proceeds - p.Cost
+	t.PnLPct = t.PnL / p.Cost * 100
+	e.l.Cash += t.Proceeds
+	e.l.Trades = append(e.l.Trades, t)
+	logging.Component("paper").Info("模拟平仓", logging.KeySymbol, p.Symbol, logging.KeyAddress, p.Address,
+		"short", p.Short, "reason", reason, "exit", fill, "pnl", t.PnL, "pnl_pct", t.PnLPct)
+}
+
+// equity 现金加持仓按最新价估值，调用方需持有 mu
+func (e *Engine) equity() float64 {
+	eq := e.l.Cash
+	for _, p := range e.l.Positions {
+		eq += p.value(p.LastPrice)
+	}
+	return eq
+}
+
+func (e *Engine) recordEquity() {
+	e.l.Equity = append(e.l.Equity, EquityPoint{Time: time.Now(), Equity: e.equity()})
+	if len(e.l.Equity) > maxEquityPoints {
+		e.l.Equity = e.l.Equity[len(e.l.Equity)-maxEquityPoints:]
+	}
+}
+
+// save 先写临时文件再替换，避免中途退出损坏账本；调用方需持有 mu
+func (e *Engine) save() {
+	data, err := json.Marshal(e.l)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(e.path), 0o755)
+	}
+	if err == nil {
+		tmp := e.path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, e.path)
+		}
+	}
+	if err != nil {
+		logging.Component("paper").Error("保存模拟盘账本失败", "path", e.path, "err", err)
+	}
+}
+
+// Close 保存账本
+func (e *Engine) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.save()
+}
+
+// Positions 当前持仓
+func (e *Engine) Positions() []Position {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	res := make([]Position, len(e.l.Positions))
+	for i, p := range e.l.Positions {
+		res[i] = *p
+	}
+	return res
+}
+
+// Trades 已平仓交易，最新在前
+func (e *Engine) Trades() []Trade {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	res := make([]Trade, len(e.l.Trades))
+	for i, t := range e.l.Trades {
+		res[len(res)-1-i] = t
+	}
+	return res
+}
+
+// Equity since 之后的权益曲线，时间升序
+func (e *Engine) Equity(since time.Time) []EquityPoint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var res []EquityPoint
+	for _, pt := range e.l.Equity {
+		if !pt.Time.Before(since) {
+			res = append(res, pt)
+		}
+	}
+	return res
+}
+
+// Stats 汇总统计
+func (e *Engine) Stats() Stats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := Stats{
+		Cash:          e.l.Cash,
+		Equity:        e.equity(),
+		OpenPositions: len(e.l.Positions),
+		Trades:        len(e.l.Trades),
+	}
+	if e.cfg.StartingBalance > 0 {
+		s.ReturnPct = (s.Equity/e.cfg.StartingBalance - 1) * 100
+	}
+
+	var gain, loss, sumPct float64
+	for _, t := range e.l.Trades {
+		s.RealizedPnL += t.PnL
+		s.TotalFees += t.Fees
+		sumPct += t.PnLPct
+		if t.PnL > 0 {
+			s.Wins++
+			gain += t.PnL
+		} else {
+			loss -= t.PnL
+		}
+	}
+	for _, p := range e.l.Positions {
+		s.TotalFees += p.Fees
+	}
+	if s.Trades > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Trades)
+		s.AvgPnLPct = sumPct / float64(s.Trades)
+	}
+	if loss > 0 {
+		s.ProfitFactor = gain / loss
+	}
+
+	peak := math.Inf(-1)
+	for _, pt := range e.l.Equity {
+		peak = max(peak, pt.Equity)
+		if peak > 0 {
+			s.MaxDrawdownPct = max(s.MaxDrawdownPct, (1-pt.Equity/peak)*100)
+		}
+	}
+	return s
+}
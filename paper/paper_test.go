@@ -0,0 +1,241 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"math"
+	"onchain-energe-SRSI/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) <= 1e-9 }
+
+func newEngine(t *testing.T, cfg Config, quotes map[string]Quote) *Engine {
+	t.Helper()
+	e, err := New(cfg, func(_ context.Context, p Position) (Quote, error) {
+		q, ok := quotes[p.Address]
+		if !ok {
+			return Quote{}, errors.New("无行情")
+		}
+		return q, nil
+	}, filepath.Join(t.TempDir(), "ledger.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestOpenFeeAndSlippage(t *testing.T) {
+	cfg := Config{StartingBalance: 100, PositionSize: 10, MaxPositions: 10, FeePct: 1, MinSlippagePct: 0.5}
+	tests := []struct {
+		name      string
+		liquidity float64
+		short     bool
+		wantEntry float64
+	}{
+		{"流动性未知取最低滑点", 0, false, 2 * 1.005},
+		{"流动性充足取最低滑点", 9900, false, 2 * 1.005},
+		{"流动性冲击 9.9/99 = 10%", 99, false, 2 * 1.1},
+		{"空头卖出价格向下滑", 99, true, 2 * 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEngine(t, cfg, nil)
+			e.Open(types.Signal{ID: "s", Address: "A", Price: 2, Liquidity: tt.liquidity, Short: tt.short})
+			ps := e.Positions()
+			if len(ps) != 1 {
+				t.Fatal("应开仓")
+			}
+			p := ps[0]
+			if !near(p.EntryPrice, tt.wantEntry) || !near(p.Fees, 0.1) || p.Cost != 10 || p.Short != tt.short {
+				t.Errorf("position = %+v", p)
+			}
+			if !near(p.Quantity, 9.9/tt.wantEntry) {
+				t.Errorf("Quantity = %v, want %v", p.Quantity, 9.9/tt.wantEntry)
+			}
+			if s := e.Stats(); s.Cash != 90 {
+				t.Errorf("Cash = %v, want 90", s.Cash)
+			}
+		})
+	}
+}
+
+func TestOpenSkips(t *testing.T) {
+	e := newEngine(t, Config{StartingBalance: 25, PositionSize: 10, MaxPositions: 2}, nil)
+	for _, addr := range []string{"A", "A", "B", "C"} {
+		e.Open(types.Signal{ID: addr, Address: addr, Price: 1})
+	}
+	if n := len(e.Positions()); n != 2 {
+		t.Errorf("持仓 %d, want 2（重复代币与超出上限跳过）", n)
+	}
+	e = newEngine(t, Config{StartingBalance: 15, PositionSize: 10, MaxPositions: 5}, nil)
+	e.Open(types.Signal{ID: "A", Address: "A", Price: 1})
+	e.Open(types.Signal{ID: "B", Address: "B", Price: 1})
+	e.Open(types.Signal{ID: "C", Address: "C", Price: 0})
+	if n := len(e.Positions()); n != 1 {
+		t.Errorf("持仓 %d, want 1（资金不足与无效价格跳过）", n)
+	}
+}
+
+func TestExitReason(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := Config{TakeProfitPct: 30, StopLossPct: 15, TrailingStopPct: 10, ExitBelowMA60: true, MaxHold: time.Hour}
+	long := func(peak float64) *Position {
+		return &Position{EntryPrice: 100, PeakPrice: peak, OpenedAt: now.Add(-time.Minute)}
+	}
+	short := func(trough float64) *Position {
+		p := long(trough)
+		p.Short = true
+		return p
+	}
+	tests := []struct {
+		name string
+		cfg  Config
+		pos  *Position
+		q    Quote
+		want string
+	}{
+		{"止盈", cfg, long(130), Quote{Price: 130}, ExitTakeProfit},
+		{"未到止盈", cfg, long(129), Quote{Price: 129}, ""},
+		{"止损", cfg, long(100), Quote{Price: 85}, ExitStopLoss},
+		{"回撤止盈", cfg, long(120), Quote{Price: 107.9}, ExitTrailing},
+		{"回撤不足", cfg, long(120), Quote{Price: 109}, ""},
+		{"未曾浮盈不触发回撤", cfg, long(100), Quote{Price: 90}, ""},
+		{"跌破 MA60", cfg, long(100), Quote{Price: 100, Close5m: 95, MA60: 100}, ExitMA60},
+		{"MA60 数据不足", cfg, long(100), Quote{Price: 100, Close5m: 95}, ""},
+		{"超过最长持有时间", cfg, &Position{EntryPrice: 100, PeakPrice: 100, OpenedAt: now.Add(-time.Hour)}, Quote{Price: 100}, ExitMaxHold},
+		{"规则为 0 不启用", Config{}, &Position{EntryPrice: 100, PeakPrice: 300, OpenedAt: now.Add(-48 * time.Hour)}, Quote{Price: 200, Close5m: 1, MA60: 100}, ""},
+		{"空头止盈", cfg, short(70), Quote{Price: 70}, ExitTakeProfit},
+		{"空头止损", cfg, short(100), Quote{Price: 115.1}, ExitStopLoss},
+		{"空头回撤止盈", cfg, short(80), Quote{Price: 88.1}, ExitTrailing},
+		{"空头回撤不足", cfg, short(80), Quote{Price: 87}, ""},
+		{"空头升破 MA60", cfg, short(100), Quote{Price: 100, Close5m: 105, MA60: 100}, ExitMA60},
+		{"空头跌破 MA60 不平仓", cfg, short(100), Quote{Price: 100, Close5m: 95, MA60: 100}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engine{cfg: tt.cfg}
+			if got := e.exitReason(tt.pos, tt.q, now); got != tt.want {
+				t.Errorf("exitReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosePnL(t *testing.T) {
+	tests := []struct {
+		name         string
+		slip         float64
+		short        bool
+		price        float64
+		wantExit     float64
+		wantProceeds float64
+	}{
+		// 10 个、均价 1、投入 10；平仓手续费 1%
+		{"多头盈利", 0, false, 2, 2, 20 - 0.2},
+		{"多头亏损", 0, false, 0.5, 0.5, 5 - 0.05},
+		{"空头盈利：归还保证金并结算差价", 0, true, 0.5, 0.5, 10*1.5 - 0.05},
+		{"空头亏损", 0, true, 1.5, 1.5, 10*0.5 - 0.15},
+		{"空头买回价格向上滑", 1, true, 0.5, 0.505, 10*1.495 - 0.0505},
+		{"多头卖出价格向下滑", 1, false, 2, 1.98, 19.8 - 0.198},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engine{cfg: Config{FeePct: 1, MinSlippagePct: tt.slip}}
+			p := &Position{Short: tt.short, EntryPrice: 1, Quantity: 10, Cost: 10, Fees: 0.1}
+			e.close(p, tt.price, ExitTakeProfit)
+			tr := e.l.Trades[0]
+			if !near(tr.ExitPrice, tt.wantExit) || !near(tr.Proceeds, tt.wantProceeds) {
+				t.Errorf("exit %v proceeds %v, want %v %v", tr.ExitPrice, tr.Proceeds, tt.wantExit, tt.wantProceeds)
+			}
+			if !near(tr.PnL, tt.wantProceeds-10) || !near(tr.PnLPct, (tt.wantProceeds-10)*10) {
+				t.Errorf("PnL = %v (%v%%)", tr.PnL, tr.PnLPct)
+			}
+			if !near(tr.Fees, 0.1+tt.wantExit*10*0.01) || !near(e.l.Cash, tt.wantProceeds) {
+				t.Errorf("fees %v cash %v", tr.Fees, e.l.Cash)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	trades := func(pnl ...float64) []Trade {
+		var res []Trade
+		for _, v := range pnl {
+			res = append(res, Trade{PnL: v, PnLPct: v * 10, Position: Position{Fees: 0.1}})
+		}
+		return res
+	}
+	equity := func(v ...float64) []EquityPoint {
+		var res []EquityPoint
+		for _, x := range v {
+			res = append(res, EquityPoint{Equity: x})
+		}
+		return res
+	}
+	tests := []struct {
+		name                    string
+		l                       ledger
+		winRate, pf, dd, avgPct float64
+	}{
+		{"有盈有亏", ledger{Cash: 106, Trades: trades(5, -2, 3), Equity: equity(100, 110, 88, 120)}, 2.0 / 3, 4, 20, 20},
+		{"无亏损时盈亏比为 0", ledger{Cash: 106, Trades: trades(5, 1), Equity: equity(100, 105, 106)}, 1, 0, 0, 30},
+		{"没有交易", ledger{Cash: 100}, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engine{cfg: Config{StartingBalance: 100}, l: tt.l}
+			s := e.Stats()
+			if !near(s.WinRate, tt.winRate) || !near(s.ProfitFactor, tt.pf) || !near(s.MaxDrawdownPct, tt.dd) || !near(s.AvgPnLPct, tt.avgPct) {
+				t.Errorf("stats = %+v", s)
+			}
+			if !near(s.ReturnPct, tt.l.Cash-100) || !near(s.TotalFees, 0.1*float64(len(tt.l.Trades))) {
+				t.Errorf("return %v fees %v", s.ReturnPct, s.TotalFees)
+			}
+		})
+	}
+}
+
+func TestCheckAndPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "paper", "ledger.json")
+	cfg := Config{StartingBalance: 100, PositionSize: 10, MaxPositions: 5, TakeProfitPct: 30}
+	quotes := map[string]Quote{"A": {Price: 2}, "B": {Price: 1.1}}
+	quote := func(_ context.Context, p Position) (Quote, error) { return quotes[p.Address], nil }
+	e, err := New(cfg, quote, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Open(types.Signal{ID: "a", Address: "A", Price: 1})
+	e.Open(types.Signal{ID: "b", Address: "B", Price: 1})
+	e.Check(context.Background())
+	if len(e.Trades()) != 1 || e.Trades()[0].Reason != ExitTakeProfit || len(e.Positions()) != 1 {
+		t.Fatalf("A 应止盈平仓, B 继续持有: trades %+v", e.Trades())
+	}
+	if p := e.Positions()[0]; p.LastPrice != 1.1 || p.PeakPrice != 1.1 {
+		t.Errorf("复查后应更新最新价与最高价: %+v", p)
+	}
+
+	restored, err := New(cfg, quote, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := e.Stats(), restored.Stats(); a != b {
+		t.Errorf("恢复后统计不一致: %+v / %+v", a, b)
+	}
+	if len(restored.Positions()) != 1 || restored.Positions()[0].ID != "b" || len(restored.Trades()) != 1 {
+		t.Errorf("恢复后持仓或交易不一致")
+	}
+	if len(restored.Equity(time.Time{})) != len(e.Equity(time.Time{})) {
+		t.Error("恢复后权益曲线不一致")
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(cfg, quote, path); err == nil {
+		t.Error("账本损坏时应返回错误")
+	}
+}
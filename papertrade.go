@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"onchain-energe-SRSI/paper"
+	"onchain-energe-SRSI/types"
+	"onchain-energe-SRSI/utils"
+	"time"
+)
+
+// paperEngine 模拟盘，未启用时为 nil
+var paperEngine *paper.Engine
+
+// startPaper 按配置创建模拟盘并开始复查持仓
+func startPaper(ctx context.Context) error {
+	pc := config.Paper
+	if !pc.Enabled {
+		return nil
+	}
+	e, err := paper.New(paper.Config{
+		StartingBalance: pc.StartingBalance,
+		PositionSize:    pc.PositionSize,
+		MaxPositions:    pc.MaxPositions,
+		FeePct:          pc.FeePct,
+		MinSlippagePct:  pc.MinSlippagePct,
+		TakeProfitPct:   pc.TakeProfitPct,
+		StopLossPct:     pc.StopLossPct,
+		TrailingStopPct: pc.TrailingStopPct,
+		ExitBelowMA60:   !pc.DisableMA60Exit,
+		MaxHold:         time.Duration(pc.MaxHoldMin) * time.Minute,
+		CheckInterval:   time.Duration(pc.CheckIntervalSec) * time.Second,
+	}, paperQuote, pc.LedgerFile)
+	if err != nil {
+		return err
+	}
+	paperEngine = e
+	go e.Run(ctx)
+	return nil
+}
+
+// paperQuote 取该池子 5m K 线：最新价为最后一根的收盘价，MA60 按已收盘 K 线计算
+func paperQuote(ctx context.Context, pos paper.Position) (paper.Quote, error) {
+	options := map[string]string{
+		"aggregate":               config.FiveAggregate,
+		"limit":                   "100",
+		"token":                   "base",
+		"currency":                "usd",
+		"include_empty_intervals": "true",
+	}
+	series, err := utils.GetSeries(ctx, types.TokenItem{
+		Chain:       pos.Chain,
+		Symbol:      pos.Symbol,
+		Address:     pos.Address,
+		PoolAddress: pos.PoolAddress,
+	}, config, options, config.Timeframe)
+	if err != nil {
+		return paper.Quote{}, err
+	}
+	closed := series.Closed().Close
+	if series.Len() == 0 || len(closed) == 0 {
+		return paper.Quote{}, fmt.Errorf("K线不足: %d", series.Len())
+	}
+	q := paper.Quote{
+		Price:   series.Close[series.Len()-1],
+		Close5m: closed[len(closed)-1],
+	}
+	if len(closed) >= 60 {
+		q.MA60 = utils.CalculateMA(closed, 60)
+	}
+	return q, nil
+}
+
+// paperEnabled 未启用模拟盘时返回 404
+func paperEnabled(w http.ResponseWriter) bool {
+	if paperEngine == nil {
+		writeError(w, http.StatusNotFound, "paper_disabled", "模拟盘未启用")
+		return false
+	}
+	return true
+}
+
+// paperHandler GET /api/paper：统计与当前持仓
+func paperHandler(w http.ResponseWriter, r *http.Request) {
+	if !paperEnabled(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"stats":     paperEngine.Stats(),
+		"positions": paperEngine.Positions(),
+	})
+}
+
+// paperTradesHandler GET /api/paper/trades?limit=&offset=，最新在前
+func paperTradesHandler(w http.ResponseWriter, r *http.Request) {
+	if !paperEnabled(w) {
+		return
+	}
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, paginate(paperEngine.Trades(), limit, offset))
+}
+
+// paperEquityHandler GET /api/paper/equity?since=，时间升序
+func paperEquityHandler(w http.ResponseWriter, r *http.Request) {
+	if !paperEnabled(w) {
+		return
+	}
+	since, err := parseSince(r.URL.Query().Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, paperEngine.Equity(since))
+}
@@ -0,0 +1,85 @@
+package perp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"onchain-energe-SRSI/metrics"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+)
+
+// binanceClient 基于 go-binance 的 USDT 本位合约公开行情，无需 API Key
+type binanceClient struct {
+	c *futures.Client
+}
+
+// NewBinance 创建币安 USDT 本位合约客户端，proxyURL 为空时直连
+func NewBinance(proxyURL string) (Client, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("代理地址无效: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	c := futures.NewClient("", "")
+	c.HTTPClient = &http.Client{Timeout: 15 * time.Second, Transport: observedTransport{transport}}
+	return &binanceClient{c: c}, nil
+}
+
+func (b *binanceClient) Symbols(ctx context.Context) ([]Symbol, error) {
+	info, err := b.c.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []Symbol
+	for _, s := range info.Symbols {
+		if s.ContractType != futures.ContractTypePerpetual || s.QuoteAsset != "USDT" || s.Status != "TRADING" {
+			continue
+		}
+		res = append(res, Symbol{Symbol: s.Symbol, BaseAsset: s.BaseAsset})
+	}
+	return res, nil
+}
+
+func (b *binanceClient) Premium(ctx context.Context, symbol string) (markPrice, fundingRate float64, err error) {
+	res, err := b.c.NewPremiumIndexService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) == 0 {
+		return 0, 0, fmt.Errorf("无溢价指数: %s", symbol)
+	}
+	if markPrice, err = strconv.ParseFloat(res[0].MarkPrice, 64); err != nil {
+		return 0, 0, fmt.Errorf("标记价格无效: %w", err)
+	}
+	if fundingRate, err = strconv.ParseFloat(res[0].LastFundingRate, 64); err != nil {
+		return 0, 0, fmt.Errorf("资金费率无效: %w", err)
+	}
+	return markPrice, fundingRate, nil
+}
+
+func (b *binanceClient) OpenInterest(ctx context.Context, symbol string) (float64, error) {
+	res, err := b.c.NewGetOpenInterestService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(res.OpenInterest, 64)
+}
+
+// observedTransport 记录请求指标
+type observedTransport struct {
+	next http.RoundTripper
+}
+
+func (t observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	metrics.ObserveRequest(metrics.ServiceBinance, start, resp, err)
+	return resp, err
+}
@@ -0,0 +1,169 @@
+package perp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/types"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Symbol 合约交易对
+type Symbol struct {
+	Symbol    string // 如 WIFUSDT、1000PEPEUSDT
+	BaseAsset string // 如 WIF、1000PEPE
+}
+
+// Client 合约行情来源，测试时可替换为假实现
+type Client interface {
+	// Symbols 当前可交易的 USDT 永续合约
+	Symbols(ctx context.Context) ([]Symbol, error)
+	// Premium 标记价格与最近一次资金费率
+	Premium(ctx context.Context, symbol string) (markPrice, fundingRate float64, err error)
+	// OpenInterest 未平仓合约数量（以标的计）
+	OpenInterest(ctx context.Context, symbol string) (float64, error)
+}
+
+// multipliers 低价代币在合约中以倍数计价，如 1000PEPE
+var multipliers = []string{"", "1000", "1000000", "1M"}
+
+// Lister 缓存合约列表，按 ticker 与手动覆盖文件把 DEX 代币映射到永续合约
+type Lister struct {
+	client       Client
+	overrideFile string
+	refresh      time.Duration
+
+	mu        sync.RWMutex
+	byBase    map[string]string // BaseAsset -> 合约
+	overrides map[string]string // 代币地址 -> 合约，空字符串表示未上线（避免同名误判）
+}
+
+// New 创建 Lister；overrideFile 为 JSON 对象 {"<代币地址>": "<合约，如 WIFUSDT，留空表示未上线>"}，可不存在
+func New(client Client, overrideFile string, refresh time.Duration) *Lister {
+	return &Lister{client: client, overrideFile: overrideFile, refresh: refresh}
+}
+
+// Run 立即加载一次，之后按间隔刷新合约列表与覆盖文件，直到 ctx 取消
+func (l *Lister) Run(ctx context.Context) {
+	logger := logging.Component("perp")
+	ticker := time.NewTicker(l.refresh)
+	defer ticker.Stop()
+	for {
+		if err := l.Refresh(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("刷新合约列表失败", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh 重新获取合约列表并读取覆盖文件；获取失败时保留旧列表
+func (l *Lister) Refresh(ctx context.Context) error {
+	overrides, ovErr := loadOverrides(l.overrideFile)
+	if ovErr == nil {
+		l.mu.Lock()
+		l.overrides = overrides
+		l.mu.Unlock()
+	}
+
+	symbols, err := l.client.Symbols(ctx)
+	if err != nil {
+		return errors.Join(err, ovErr)
+	}
+	byBase := make(map[string]string, len(symbols))
+	for _, s := range symbols {
+		byBase[strings.ToUpper(s.BaseAsset)] = s.Symbol
+	}
+	l.mu.Lock()
+	l.byBase = byBase
+	l.mu.Unlock()
+	return ovErr
+}
+
+func loadOverrides(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取合约覆盖文件失败: %w", err)
+	}
+	var m map[string]string
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("解析合约覆盖文件失败: %w", err)
+	}
+	return m, nil
+}
+
+// Lookup 返回代币对应的永续合约：覆盖文件优先，否则按 ticker（含倍数前缀）匹配
+func (l *Lister) Lookup(address, symbol string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if s, ok := l.overrides[address]; ok {
+		return s, s != ""
+	}
+	ticker := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(symbol, "$")))
+	if ticker == "" {
+		return "", false
+	}
+	for _, m := range multipliers {
+		if s, ok := l.byBase[m+ticker]; ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Listing 查询代币是否有永续合约，有则附带资金费率与持仓量；未上线时返回 nil
+func (l *Lister) Listing(ctx context.Context, address, symbol string) (*types.PerpListing, error) {
+	s, ok := l.Lookup(address, symbol)
+	if !ok {
+		return nil, nil
+	}
+	res := &types.PerpListing{Exchange: "binance", Symbol: s}
+	mark, funding, err := l.client.Premium(ctx, s)
+	if err != nil {
+		return res, err
+	}
+	res.MarkPrice, res.FundingRate = mark, funding
+	oi, err := l.client.OpenInterest(ctx, s)
+	if err != nil {
+		return res, err
+	}
+	res.OpenInterest = oi
+	res.OpenInterestUSD = oi * mark
+	return res, nil
+}
+
+// Format 告警中附加的一行
+func Format(p *types.PerpListing) string {
+	s := fmt.Sprintf("🔗 币安永续 %s", p.Symbol)
+	if p.MarkPrice > 0 {
+		s += fmt.Sprintf(" | 资金费率 %.4f%% | 持仓量 %s USDT", p.FundingRate*100, compact(p.OpenInterestUSD))
+	}
+	return s
+}
+
+// compact 以 K/M/B 简写金额
+func compact(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fB", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fM", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.1fK", v/1e3)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
@@ -0,0 +1,127 @@
+package perp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeClient 假合约行情
+type fakeClient struct {
+	symbols []Symbol
+	err     error
+	mark    float64
+	funding float64
+	oi      float64
+}
+
+func (f *fakeClient) Symbols(context.Context) ([]Symbol, error) { return f.symbols, f.err }
+
+func (f *fakeClient) Premium(context.Context, string) (float64, float64, error) {
+	return f.mark, f.funding, nil
+}
+
+func (f *fakeClient) OpenInterest(context.Context, string) (float64, error) { return f.oi, nil }
+
+func writeOverrides(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "perp_overrides.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLookup(t *testing.T) {
+	client := &fakeClient{symbols: []Symbol{
+		{Symbol: "WIFUSDT", BaseAsset: "WIF"},
+		{Symbol: "1000PEPEUSDT", BaseAsset: "1000PEPE"},
+		{Symbol: "1000000MOGUSDT", BaseAsset: "1000000MOG"},
+		{Symbol: "1MBABYDOGEUSDT", BaseAsset: "1MBABYDOGE"},
+		{Symbol: "BONKUSDT", BaseAsset: "BONK"},
+	}}
+	overrides := writeOverrides(t, `{"addrFake": "", "addrBonk": "1000BONKUSDT"}`)
+	l := New(client, overrides, 0)
+	if err := l.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, address, symbol string
+		want                  string
+		ok                    bool
+	}{
+		{"直接匹配", "a1", "WIF", "WIFUSDT", true},
+		{"忽略大小写与 $ 前缀", "a2", "$wif", "WIFUSDT", true},
+		{"1000 倍前缀", "a3", "PEPE", "1000PEPEUSDT", true},
+		{"1000000 倍前缀", "a4", "MOG", "1000000MOGUSDT", true},
+		{"1M 倍前缀", "a5", "BABYDOGE", "1MBABYDOGEUSDT", true},
+		{"未上线", "a6", "NOPE", "", false},
+		{"空 ticker", "a7", " ", "", false},
+		{"覆盖文件优先于 ticker 匹配", "addrBonk", "BONK", "1000BONKUSDT", true},
+		{"覆盖为空表示未上线（同名仿盘）", "addrFake", "WIF", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := l.Lookup(tt.address, tt.symbol)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Lookup(%q, %q) = %q, %v, want %q, %v", tt.address, tt.symbol, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRefreshKeepsListOnError(t *testing.T) {
+	client := &fakeClient{symbols: []Symbol{{Symbol: "WIFUSDT", BaseAsset: "WIF"}}}
+	l := New(client, "", 0)
+	if err := l.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	client.symbols, client.err = nil, errors.New("exchangeInfo 503")
+	if err := l.Refresh(context.Background()); err == nil {
+		t.Fatal("获取失败时应返回错误")
+	}
+	if s, ok := l.Lookup("a", "WIF"); !ok || s != "WIFUSDT" {
+		t.Errorf("获取失败后应保留旧列表, got %q, %v", s, ok)
+	}
+}
+
+func TestRefreshBadOverrideKeepsOld(t *testing.T) {
+	path := writeOverrides(t, `{"addr": ""}`)
+	l := New(&fakeClient{}, path, 0)
+	if err := l.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Refresh(context.Background()); err == nil {
+		t.Error("覆盖文件无效时应返回错误")
+	}
+	l.byBase = map[string]string{"WIF": "WIFUSDT"}
+	if _, ok := l.Lookup("addr", "WIF"); ok {
+		t.Error("覆盖文件解析失败时应保留旧的覆盖")
+	}
+}
+
+func TestListing(t *testing.T) {
+	l := New(&fakeClient{symbols: []Symbol{{Symbol: "WIFUSDT", BaseAsset: "WIF"}}, mark: 2, funding: 0.0001, oi: 1_500_000}, "", 0)
+	if err := l.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	p, err := l.Listing(context.Background(), "a", "WIF")
+	if err != nil || p == nil {
+		t.Fatalf("Listing = %v, %v", p, err)
+	}
+	if p.OpenInterestUSD != 3_000_000 {
+		t.Errorf("OpenInterestUSD = %v", p.OpenInterestUSD)
+	}
+	if got, want := Format(p), "🔗 币安永续 WIFUSDT | 资金费率 0.0100% | 持仓量 3.00M USDT"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+	if p, _ := l.Listing(context.Background(), "b", "NOPE"); p != nil {
+		t.Errorf("未上线时应返回 nil, got %v", p)
+	}
+}
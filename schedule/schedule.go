@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+)
+
+// Timeframe K 线周期，收盘时间按 UTC 对齐（与 GeckoTerminal 聚合一致）
+type Timeframe struct {
+	Name   string
+	Period time.Duration
+}
+
+var (
+	M1  = Timeframe{"1m", time.Minute}
+	M5  = Timeframe{"5m", 5 * time.Minute}
+	M15 = Timeframe{"15m", 15 * time.Minute}
+	H1  = Timeframe{"1h", time.Hour}
+	H4  = Timeframe{"4h", 4 * time.Hour}
+)
+
+// Cascade 级联策略使用的周期，从高到低
+var Cascade = []Timeframe{H4, H1, M15, M5, M1}
+
+// LastClose t 时刻之前最近一根已收盘 K 线的收盘时间（即当前 K 线的开盘时间）
+func (tf Timeframe) LastClose(t time.Time) time.Time {
+	return t.Truncate(tf.Period)
+}
+
+// NextClose t 之后的下一个收盘时间
+func (tf Timeframe) NextClose(t time.Time) time.Time {
+	return tf.LastClose(t).Add(tf.Period)
+}
+
+// ClosedAt 返回在 close 时刻收盘的周期
+func ClosedAt(close time.Time, tfs []Timeframe) []Timeframe {
+	var closed []Timeframe
+	for _, tf := range tfs {
+		if tf.LastClose(close).Equal(close) {
+			closed = append(closed, tf)
+		}
+	}
+	return closed
+}
+
+// Settle 收盘后等待接口数据就绪的时长，根据观测到的延迟自适应调整
+type Settle struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	min, max time.Duration
+	adaptive bool
+}
+
+// settleMargin 在观测延迟之上保留的余量
+const settleMargin = time.Second
+
+// NewSettle initial 为初始等待时长，adaptive 为 false 时始终使用 initial
+func NewSettle(initial, min, max time.Duration, adaptive bool) *Settle {
+	s := &Settle{min: min, max: max, adaptive: adaptive}
+	s.delay = s.clamp(initial)
+	return s
+}
+
+// Delay 当前等待时长
+func (s *Settle) Delay() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.delay
+}
+
+// Max 等待上限，超过后不再等待新 K 线
+func (s *Settle) Max() time.Duration {
+	return s.max
+}
+
+// Observe 记录一次从收盘到新 K 线可见的延迟。
+// 延迟变大时立即跟上，变小时缓慢回落，避免在接口抖动时取到未收盘数据
+func (s *Settle) Observe(lag time.Duration) {
+	if !s.adaptive {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target := lag + settleMargin
+	if target > s.delay {
+		s.delay = s.clamp(target)
+		return
+	}
+	s.delay = s.clamp(s.delay - (s.delay-target)/4)
+}
+
+func (s *Settle) clamp(d time.Duration) time.Duration {
+	return min(max(d, s.min), s.max)
+}
@@ -0,0 +1,169 @@
+package stream
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 事件类型
+const (
+	EventSignal     = "signal"      // 策略信号
+	EventFirstAlert = "first_alert" // 首次警报
+	EventBanList    = "ban_list"    // 封禁列表变化
+	EventScan       = "scan"        // 一轮扫描结束的概况
+)
+
+// Event 推送给客户端的事件，ID 单调递增（跨重启也递增），用于断线续传
+type Event struct {
+	ID    int64     `json:"id"`
+	Type  string    `json:"type"`
+	Chain string    `json:"chain,omitempty"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// Filter 按事件类型与链过滤，字段为空表示不限
+type Filter struct {
+	Types  map[string]bool
+	Chains map[string]bool
+}
+
+// ParseFilter 从 ?types=signal,scan&chains=solana 解析过滤条件
+func ParseFilter(types, chains string) Filter {
+	return Filter{Types: parseSet(types), Chains: parseSet(chains)}
+}
+
+func parseSet(s string) map[string]bool {
+	if s == "" {
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// Match 不带链信息的事件（如扫描概况）不受链过滤影响
+func (f Filter) Match(e Event) bool {
+	if len(f.Types) > 0 && !f.Types[e.Type] {
+		return false
+	}
+	if len(f.Chains) > 0 && e.Chain != "" && !f.Chains[e.Chain] {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	ch     chan Event
+	filter Filter
+}
+
+// subscriberBuffer 订阅者缓冲，写满说明客户端过慢，直接断开让其续传
+const subscriberBuffer = 64
+
+// Hub 事件广播中心，保留最近的事件供重连回放
+type Hub struct {
+	mu     sync.Mutex
+	nextID int64
+	buffer []Event
+	size   int
+	subs   map[*subscriber]struct{}
+	closed bool
+}
+
+// NewHub bufferSize 为可回放的事件数量
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		nextID: epochID(time.Now()),
+		size:   max(bufferSize, 1),
+		subs:   make(map[*subscriber]struct{}),
+	}
+}
+
+// epochID 以启动时间（毫秒 × 1000）作为 ID 起点：重启后的 ID 总大于上一次运行发出的 ID
+// （除非上次运行平均每毫秒超过 1000 条事件），客户端带旧的 Last-Event-ID 重连时不会漏掉新事件；
+// 数值小于 2^53，JavaScript 客户端可精确表示
+func epochID(start time.Time) int64 {
+	return start.UnixMilli() * 1000
+}
+
+// Publish 广播一条事件
+func (h *Hub) Publish(typ, chain string, data any) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	e := Event{ID: h.nextID, Type: typ, Chain: chain, Time: time.Now(), Data: data}
+	h.buffer = append(h.buffer, e)
+	if len(h.buffer) > h.size {
+		h.buffer = h.buffer[len(h.buffer)-h.size:]
+	}
+
+	for s := range h.subs {
+		if !s.filter.Match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			delete(h.subs, s)
+			close(s.ch)
+		}
+	}
+	return e
+}
+
+// Subscribe 返回 lastID 之后仍在缓冲内的匹配事件，以及后续事件的通道。
+// 通道被关闭表示订阅已结束（客户端过慢），cancel 可重复调用
+func (h *Hub) Subscribe(f Filter, lastID int64) (replay []Event, events <-chan Event, cancel func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, e := range h.buffer {
+		if e.ID > lastID && f.Match(e) {
+			replay = append(replay, e)
+		}
+	}
+	s := &subscriber{ch: make(chan Event, subscriberBuffer), filter: f}
+	if h.closed {
+		close(s.ch)
+		return replay, s.ch, func() {}
+	}
+	h.subs[s] = struct{}{}
+
+	cancel = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[s]; ok {
+			delete(h.subs, s)
+			close(s.ch)
+		}
+	}
+	return replay, s.ch, cancel
+}
+
+// Close 结束所有订阅，之后的订阅立即结束；用于 HTTP 服务关闭时释放长连接
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		delete(h.subs, s)
+		close(s.ch)
+	}
+}
+
+// parseLastEventID 解析 Last-Event-ID，无效时为 0（不回放历史）
+func parseLastEventID(s string) int64 {
+	id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,152 @@
+package stream
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func ids(events []Event) []int64 {
+	var res []int64
+	for _, e := range events {
+		res = append(res, e.ID)
+	}
+	return res
+}
+
+func TestEpochID(t *testing.T) {
+	newHub := func(start time.Time) *Hub {
+		h := NewHub(10)
+		h.nextID = epochID(start)
+		return h
+	}
+	start := time.Now()
+	prev := newHub(start)
+	var last Event
+	for range 5 {
+		last = prev.Publish(EventSignal, "solana", nil)
+	}
+	// 模拟重启：新 Hub 的第一条事件 ID 大于旧 Hub 发出的所有 ID
+	next := newHub(start.Add(time.Millisecond))
+	if e := next.Publish(EventSignal, "solana", nil); e.ID <= last.ID {
+		t.Errorf("重启后 ID %d 不大于重启前的 %d", e.ID, last.ID)
+	}
+	if id := epochID(start); id >= 1<<53 {
+		t.Errorf("epochID %d 超出 JavaScript 安全整数范围", id)
+	}
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	h := NewHub(3)
+	var published []Event
+	for range 5 {
+		published = append(published, h.Publish(EventSignal, "solana", nil))
+	}
+	tests := []struct {
+		name   string
+		lastID int64
+		want   []int64
+	}{
+		{"不带 Last-Event-ID 回放整个缓冲", 0, ids(published[2:])},
+		{"只回放之后的事件", published[3].ID, ids(published[4:])},
+		{"已是最新", published[4].ID, nil},
+		{"超出缓冲的只回放缓冲内的", published[0].ID, ids(published[2:])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, _, cancel := h.Subscribe(Filter{}, tt.lastID)
+			defer cancel()
+			if got := ids(replay); !slices.Equal(got, tt.want) {
+				t.Errorf("replay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	h := NewHub(10)
+	h.Publish(EventSignal, "solana", nil)
+	h.Publish(EventSignal, "bsc", nil)
+	h.Publish(EventScan, "", nil)
+	h.Publish(EventFirstAlert, "solana", nil)
+
+	tests := []struct {
+		name          string
+		types, chains string
+		want          []string
+	}{
+		{"不限", "", "", []string{"signal/solana", "signal/bsc", "scan/", "first_alert/solana"}},
+		{"按类型", "signal", "", []string{"signal/solana", "signal/bsc"}},
+		{"按链，不带链的事件不受影响", "", "solana", []string{"signal/solana", "scan/", "first_alert/solana"}},
+		{"类型与链同时过滤", "signal, first_alert", "bsc", []string{"signal/bsc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := ParseFilter(tt.types, tt.chains)
+			replay, events, cancel := h.Subscribe(f, 0)
+			defer cancel()
+			var got []string
+			for _, e := range replay {
+				got = append(got, e.Type+"/"+e.Chain)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("回放 = %v, want %v", got, tt.want)
+			}
+			// 实时事件同样按条件过滤
+			h.Publish(EventBanList, "eth", nil)
+			select {
+			case e := <-events:
+				if !f.Match(e) {
+					t.Errorf("收到不匹配的事件 %+v", e)
+				}
+			default:
+			}
+		})
+	}
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	h := NewHub(1)
+	_, slow, cancelSlow := h.Subscribe(Filter{}, 0)
+	defer cancelSlow()
+	_, other, cancelOther := h.Subscribe(ParseFilter(EventScan, ""), 0)
+	defer cancelOther()
+
+	// 慢订阅者缓冲写满后再来一条即被断开
+	for range subscriberBuffer + 1 {
+		h.Publish(EventSignal, "solana", nil)
+	}
+	n := 0
+	for range slow {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("断开前收到 %d 条, want %d", n, subscriberBuffer)
+	}
+
+	// 未写满的订阅者不受影响
+	h.Publish(EventScan, "", nil)
+	select {
+	case e, ok := <-other:
+		if !ok || e.Type != EventScan {
+			t.Errorf("其他订阅者应继续收到事件: %+v, %v", e, ok)
+		}
+	default:
+		t.Error("其他订阅者未收到事件")
+	}
+	cancelSlow() // 已断开后再取消不应 panic
+}
+
+func TestClose(t *testing.T) {
+	h := NewHub(1)
+	_, events, cancel := h.Subscribe(Filter{}, 0)
+	defer cancel()
+	h.Close()
+	if _, ok := <-events; ok {
+		t.Error("Close 后订阅通道应关闭")
+	}
+	_, events, _ = h.Subscribe(Filter{}, 0)
+	if _, ok := <-events; ok {
+		t.Error("Close 后的订阅应立即结束")
+	}
+}
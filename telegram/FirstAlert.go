@@ -4,9 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
-	"net/url"
+	"onchain-energe-SRSI/logging"
 	"regexp"
 	"strings"
 	"time"
@@ -17,47 +16,25 @@ var (
 	alertChatID   = "6074996357"
 )
 
-// sendRawMessage 与 SendMessage 类似，但**不会**把发送记录加入 savedMessages，避免循环调用
-func sendRawMessage(botToken, chatID, text string) error {
-	proxy := "http://127.0.0.1:10809"
-	proxyURL, err := url.Parse(proxy)
-	if err != nil {
-		return fmt.Errorf("解析代理地址失败: %w", err)
-	}
+// AlertBot 返回首次警报 bot 的 token 与 chat
+func AlertBot() (botToken, chatID string) {
+	return alertBotToken, alertChatID
+}
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			Proxy: http.ProxyURL(proxyURL),
-		},
-		Timeout: 10 * time.Second,
-	}
-	url := fmt.Sprintf("%s%s/sendMessage", telegramAPIURL, botToken)
+// FirstAlertEvent 判定为首次警报的消息
+type FirstAlertEvent struct {
+	Symbol    string    `json:"symbol"`
+	Text      string    `json:"text"`       // 原始消息
+	AlertText string    `json:"alert_text"` // 告警文本
+	Reason    string    `json:"reason"`
+	Time      time.Time `json:"time"`
+}
 
-	message := Message{ChatID: chatID, Text: text}
-	jsonMessage, err := json.Marshal(message)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
-	}
+var firstAlertHandler func(FirstAlertEvent)
 
-	backoff := 1 * time.Second
-	var lastErr error
-	for attempt := 1; attempt <= 3; attempt++ {
-		resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonMessage))
-		if err == nil && resp.StatusCode == http.StatusOK {
-			resp.Body.Close()
-			return nil
-		}
-		if err != nil {
-			lastErr = fmt.Errorf("发送失败 (尝试 %d): %w", attempt, err)
-		} else {
-			lastErr = fmt.Errorf("非 200 返回 (尝试 %d): %s", attempt, resp.Status)
-			resp.Body.Close()
-		}
-		log.Printf("[Telegram raw] ❌ %v，等待 %v 后重试", lastErr, backoff)
-		time.Sleep(backoff)
-		backoff *= 2
-	}
-	return fmt.Errorf("最终发送失败: %w", lastErr)
+// SetFirstAlertHandler 设置首次警报的分发函数（如通知路由），未设置时直接发往 alert bot
+func SetFirstAlertHandler(fn func(FirstAlertEvent)) {
+	firstAlertHandler = fn
 }
 
 // ------- 分析逻辑实现 -------
@@ -134,20 +111,40 @@ func AnalyzeNewMessage(msg SavedMessage) {
 		return
 	}
 
+	// 构造告警文本（简洁）
+	alertText := fmt.Sprintf("🔔 <短线>\n消息: %s\n时间: %s\n原因: %s", msg.Text, msg.Timestamp.Format(customLayout), reason)
+
+	if firstAlertHandler != nil {
+		firstAlertHandler(FirstAlertEvent{
+			Symbol:    symbol,
+			Text:      msg.Text,
+			AlertText: alertText,
+			Reason:    reason,
+			Time:      msg.Timestamp,
+		})
+		saveAlertToAPI("短线", msg.Text, reason)
+		return
+	}
+
 	// 配置检查
 	if alertBotToken == "" || alertChatID == "" {
-		log.Printf("[Alert] 首次警报 (%s) 被判定, 但 alert bot 未配置，消息: %s", symbol, msg.Text)
+		logging.Component("alert").Warn("首次警报已判定，但 alert bot 未配置", logging.KeySymbol, symbol, "text", msg.Text)
 		return
 	}
 
-	// 构造告警文本（简洁）
-	alertText := fmt.Sprintf("🔔 <短线>\n消息: %s\n时间: %s\n原因: %s", msg.Text, msg.Timestamp.Format(customLayout), reason)
-
-	if err := sendRawMessage(alertBotToken, alertChatID, alertText); err != nil {
-		log.Printf("[Alert] 发送首次警报失败: %v", err)
-	} else {
-		// 保存到 API，category 可选 "短线" 或 "中线"
-		saveAlertToAPI("短线", msg.Text, reason)
+	// 首次警报高优先级入队，不写入 savedMessages，避免循环调用
+	err := Enqueue(OutboundMessage{
+		BotToken: alertBotToken,
+		ChatID:   alertChatID,
+		Text:     alertText,
+		Priority: PriorityHigh,
+		OnSent: func(int) {
+			// 保存到 API，category 可选 "短线" 或 "中线"
+			saveAlertToAPI("短线", msg.Text, reason)
+		},
+	})
+	if err != nil {
+		logging.Component("alert").Error("首次警报入队失败", logging.KeySymbol, symbol, "err", err)
 	}
 }
 
@@ -163,11 +160,11 @@ func saveAlertToAPI(category, text, reason string) {
 	data, _ := json.Marshal(body)
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
 	if err != nil {
-		log.Printf("[Alert] 保存到API失败: %v", err)
+		logging.Component("alert").Error("保存警报到 API 失败", "err", err)
 		return
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[Alert] 保存到API失败, 状态码: %d", resp.StatusCode)
+		logging.Component("alert").Error("保存警报到 API 失败", "status", resp.StatusCode)
 	}
 }
@@ -2,16 +2,45 @@ package telegram
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
 	"sync"
 	"time"
 )
 
-const telegramAPIURL = "https://api.telegram.org/bot"
+// telegramAPIURL Bot API 地址前缀，可通过 SetAPIURL 指向本地假服务
+var telegramAPIURL = "https://api.telegram.org/bot"
+
+// SetAPIURL 修改 Bot API 地址前缀（形如 http://127.0.0.1:8081/bot），需在发送前调用
+func SetAPIURL(u string) {
+	if u != "" {
+		telegramAPIURL = u
+	}
+}
+
+// httpTransport 发送消息与图片共用，SetProxy 之前直连
+var httpTransport http.RoundTripper = &http.Transport{}
+
+// SetProxy 出站请求使用的代理（与 Bot 命令轮询一致），为空时直连；需在发送前调用
+func SetProxy(proxy string) error {
+	t := &http.Transport{}
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return fmt.Errorf("解析代理地址失败: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+	httpTransport = t
+	return nil
+}
 
 type Message struct {
 	ChatID string `json:"chat_id"`
@@ -36,59 +65,92 @@ var (
 )
 
 // SendMessage 发送普通文本 Telegram 消息，包含指数退避重试
-func SendMessage(botToken, chatID, text string) error {
-	proxy := "http://127.0.0.1:10809"
-	proxyURL, err := url.Parse(proxy)
-	if err != nil {
-		// 错误注释：代理地址解析失败，通常由于配置错误
-		return fmt.Errorf("解析代理地址失败: %w", err)
+func SendMessage(ctx context.Context, botToken, chatID, text string) error {
+	if _, err := postMessage(ctx, botToken, chatID, text, "", 0); err != nil {
+		return err
 	}
+	// 成功发送消息，保存
+	AddMessage(SavedMessage{
+		Text:      text,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
 
-	transport := &http.Transport{
-		Proxy: http.ProxyURL(proxyURL),
+type MarkdownMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+// SendMarkdownMessage 发送 Markdown 格式的 Telegram 消息，包含指数退避重试
+func SendMarkdownMessage(ctx context.Context, botToken, chatID, text string) error {
+	if _, err := postMessage(ctx, botToken, chatID, text, "Markdown", 0); err != nil {
+		return err
 	}
+	AddMessage(SavedMessage{
+		Text:      text,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
 
+// postMessage 调用 sendMessage 接口，包含指数退避重试；不写入 savedMessages。
+// replyTo 非 0 时作为对该消息的回复，返回新消息的 message_id；ctx 取消时中断请求与重试等待
+func postMessage(ctx context.Context, botToken, chatID, text, parseMode string, replyTo int) (int, error) {
 	client := &http.Client{
-		Transport: transport,
+		Transport: httpTransport,
 		Timeout:   10 * time.Second, // 每条请求超时 10 秒
 	}
 	url := fmt.Sprintf("%s%s/sendMessage", telegramAPIURL, botToken)
 
-	message := Message{
-		ChatID: chatID,
-		Text:   text,
+	payload := map[string]any{"chat_id": chatID, "text": text}
+	if parseMode != "" {
+		payload["parse_mode"] = parseMode
+	}
+	if replyTo != 0 {
+		payload["reply_to_message_id"] = replyTo
+		payload["allow_sending_without_reply"] = true
 	}
 
-	jsonMessage, err := json.Marshal(message)
+	jsonMessage, err := json.Marshal(payload)
 	if err != nil {
 		// 错误注释：JSON 序列化失败，通常由于消息结构不合法
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return 0, fmt.Errorf("failed to marshal message: %w", err)
 	}
 
 	const maxRetries = 3
-	const baseDelay = 1 * time.Second // 初始延迟 100ms
-	const maxDelay = 10 * time.Second // 最大延迟 1000ms
+	const baseDelay = 1 * time.Second // 初始延迟 1s
+	const maxDelay = 10 * time.Second // 最大延迟 10s
 	const jitterFactor = 0.1          // 抖动因子 ±10%
 
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonMessage))
+		var retryAfter time.Duration
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonMessage))
+		if err != nil {
+			return 0, fmt.Errorf("failed to build request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if attempt > 1 {
+			metrics.Retries.Inc(metrics.ServiceTelegram)
+		}
+		start := time.Now()
+		resp, err := client.Do(req)
+		metrics.ObserveRequest(metrics.ServiceTelegram, start, resp, err)
 		if err != nil {
 			// 错误注释：网络请求失败（例如网络中断或超时）
 			lastErr = fmt.Errorf("failed to send message (attempt %d/%d): %w", attempt, maxRetries, err)
 		} else {
-			// 检查 HTTP 状态码
-			if resp.StatusCode != http.StatusOK {
-				// 错误注释：非 200 状态码，通常由于 Telegram API 限流或参数错误
-				lastErr = fmt.Errorf("received non-200 response (attempt %d/%d): %s", attempt, maxRetries, resp.Status)
-			} else {
-				// 成功发送消息，保存并返回
-				AddMessage(SavedMessage{
-					Text:      text,
-					Timestamp: time.Now(),
-				})
+			if resp.StatusCode == http.StatusOK {
+				messageID := parseMessageID(resp.Body)
 				resp.Body.Close()
-				return nil
+				return messageID, nil
+			}
+			// 错误注释：非 200 状态码，通常由于 Telegram API 限流或参数错误
+			lastErr = fmt.Errorf("received non-200 response (attempt %d/%d): %s", attempt, maxRetries, resp.Status)
+			if resp.StatusCode == http.StatusTooManyRequests {
+				retryAfter = parseRetryAfter(resp.Body)
 			}
 			resp.Body.Close()
 		}
@@ -102,98 +164,59 @@ func SendMessage(botToken, chatID, text string) error {
 		// 添加 ±10% 抖动
 		jitter := float64(delay) * jitterFactor * (rand.Float64() - 0.5) * 2
 		totalDelay := time.Duration(float64(delay) + jitter)
+		// 429 时以 Telegram 给出的 retry_after 为准
+		if retryAfter > totalDelay {
+			totalDelay = retryAfter
+		}
 
 		// 错误注释：等待指数退避延迟后重试，避免频繁请求导致限流
-		fmt.Printf("Telegram 发送失败 (%s)，将在 %v 后重试 (尝试 %d/%d): %v\n", text, totalDelay, attempt, maxRetries, err)
-		time.Sleep(totalDelay)
+		logging.Component("telegram").Warn("消息发送失败，稍后重试", "chat_id", chatID, "retry_in", totalDelay,
+			"attempt", attempt, "max_attempts", maxRetries, "err", lastErr)
+		if err := sleepContext(ctx, totalDelay); err != nil {
+			return 0, fmt.Errorf("发送中断: %w", lastErr)
+		}
 	}
 
 	// 错误注释：重试耗尽后返回最后一次错误，需检查 Telegram API 状态或网络
-	return fmt.Errorf("多次发送失败: %w", lastErr)
+	return 0, fmt.Errorf("多次发送失败: %w", lastErr)
 }
 
-type MarkdownMessage struct {
-	ChatID    string `json:"chat_id"`
-	Text      string `json:"text"`
-	ParseMode string `json:"parse_mode"`
-}
-
-// SendMarkdownMessage 发送 Markdown 格式的 Telegram 消息，包含指数退避重试
-func SendMarkdownMessage(botToken, chatID, text string) error {
-	proxy := "http://127.0.0.1:10809"
-	proxyURL, err := url.Parse(proxy)
-	if err != nil {
-		// 错误注释：代理地址解析失败，通常由于配置错误
-		return fmt.Errorf("解析代理地址失败: %w", err)
+// parseMessageID 解析成功响应中的 result.message_id，失败时返回 0
+func parseMessageID(body io.Reader) int {
+	var r struct {
+		Result struct {
+			MessageID int `json:"message_id"`
+		} `json:"result"`
 	}
-
-	transport := &http.Transport{
-		Proxy: http.ProxyURL(proxyURL),
+	if err := json.NewDecoder(body).Decode(&r); err != nil {
+		return 0
 	}
+	return r.Result.MessageID
+}
 
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   10 * time.Second, // 每条请求超时 10 秒
-	}
-	url := fmt.Sprintf("%s%s/sendMessage", telegramAPIURL, botToken)
-
-	message := MarkdownMessage{
-		ChatID:    chatID,
-		Text:      text,
-		ParseMode: "Markdown",
+// parseRetryAfter 解析 429 响应中的 parameters.retry_after（秒）
+func parseRetryAfter(body io.Reader) time.Duration {
+	var r struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
 	}
-
-	jsonMessage, err := json.Marshal(message)
-	if err != nil {
-		// 错误注释：JSON 序列化失败，通常由于消息结构不合法
-		return fmt.Errorf("failed to marshal markdown message: %w", err)
+	if err := json.NewDecoder(body).Decode(&r); err != nil {
+		return 0
 	}
+	return time.Duration(r.Parameters.RetryAfter) * time.Second
+}
 
-	const maxRetries = 3
-	const baseDelay = 1 * time.Second // 初始延迟 100ms
-	const maxDelay = 10 * time.Second // 最大延迟 1000ms
-	const jitterFactor = 0.1          // 抖动因子 ±10%
-
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonMessage))
-		if err != nil {
-			// 错误注释：网络请求失败（例如网络中断或超时）
-			lastErr = fmt.Errorf("failed to send markdown message (attempt %d/%d): %w", attempt, maxRetries, err)
-		} else {
-			// 检查 HTTP 状态码
-			if resp.StatusCode != http.StatusOK {
-				// 错误注释：非 200 状态码，通常由于 Telegram API 限流或参数错误
-				lastErr = fmt.Errorf("received non-200 response (attempt %d/%d): %s", attempt, maxRetries, resp.Status)
-			} else {
-				// 成功发送消息，保存并返回
-				AddMessage(SavedMessage{
-					Text:      text,
-					Timestamp: time.Now(),
-				})
-				resp.Body.Close()
-				return nil
-			}
-			resp.Body.Close()
-		}
-
-		// 计算指数退避延迟
-		// 延迟 = baseDelay * 2^(attempt-1) + 随机抖动
-		delay := baseDelay * (1 << (attempt - 1))
-		if delay > maxDelay {
-			delay = maxDelay
-		}
-		// 添加 ±10% 抖动
-		jitter := float64(delay) * jitterFactor * (rand.Float64() - 0.5) * 2
-		totalDelay := time.Duration(float64(delay) + jitter)
-
-		// 错误注释：等待指数退避延迟后重试，避免频繁请求导致限流
-		fmt.Printf("Telegram 发送失败 (%s)，将在 %v 后重试 (尝试 %d/%d)\n", text, totalDelay, attempt, maxRetries)
-		time.Sleep(totalDelay)
+// sleepContext 等待 d，ctx 先取消时提前返回 ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
-
-	// 错误注释：重试耗尽后返回最后一次错误，需检查 Telegram API 状态或网络
-	return fmt.Errorf("多次发送失败: %w", lastErr)
 }
 
 // AddMessage 添加一条消息，超出maxSize自动删除最早的
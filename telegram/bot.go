@@ -0,0 +1,199 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
+	"strings"
+	"time"
+)
+
+// CommandFunc 处理一条命令，返回回复文本；args 不含命令本身
+type CommandFunc func(args []string) string
+
+// Update getUpdates 返回的单条更新（只取用到的字段）
+type Update struct {
+	UpdateID int `json:"update_id"`
+	Message  *struct {
+		MessageID int    `json:"message_id"`
+		Text      string `json:"text"`
+		Chat      struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+type updatesResponse struct {
+	OK          bool     `json:"ok"`
+	Result      []Update `json:"result"`
+	Description string   `json:"description"`
+}
+
+// Bot 以长轮询 getUpdates 的方式处理授权 chat 的命令
+type Bot struct {
+	Token       string
+	APIURL      string        // 默认 telegramAPIURL，测试时可指向本地假服务
+	Client      *http.Client  // 需大于 PollTimeout
+	PollTimeout time.Duration // getUpdates 长轮询时长
+
+	allowed  map[string]bool
+	handlers map[string]CommandFunc
+	offset   int
+}
+
+// NewBot 创建命令机器人，allowedChats 为空时拒绝所有命令
+func NewBot(token, proxy string, allowedChats []string) (*Bot, error) {
+	client := &http.Client{Timeout: 40 * time.Second}
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("解析代理地址失败: %w", err)
+		}
+		client.Transport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	}
+
+	b := &Bot{
+		Token:       token,
+		APIURL:      telegramAPIURL,
+		Client:      client,
+		PollTimeout: 30 * time.Second,
+		allowed:     make(map[string]bool),
+		handlers:    make(map[string]CommandFunc),
+	}
+	for _, id := range allowedChats {
+		b.allowed[id] = true
+	}
+	return b, nil
+}
+
+// Handle 注册命令，如 Handle("/status", fn)
+func (b *Bot) Handle(command string, fn CommandFunc) {
+	b.handlers[strings.ToLower(command)] = fn
+}
+
+// Run 持续拉取更新直到 ctx 取消
+func (b *Bot) Run(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		updates, err := b.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logging.Component("bot").Warn("getUpdates 失败，稍后重试", "retry_in", backoff, "err", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, time.Minute)
+			continue
+		}
+		backoff = time.Second
+
+		for _, u := range updates {
+			b.offset = u.UpdateID + 1
+			b.dispatch(ctx, u)
+		}
+	}
+}
+
+func (b *Bot) getUpdates(ctx context.Context) ([]Update, error) {
+	params := url.Values{}
+	params.Set("timeout", fmt.Sprint(int(b.PollTimeout.Seconds())))
+	params.Set("offset", fmt.Sprint(b.offset))
+	params.Set("allowed_updates", `["message"]`)
+	reqURL := fmt.Sprintf("%s%s/getUpdates?%s", b.APIURL, b.Token, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r updatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("解析 getUpdates 失败 (%s): %w", resp.Status, err)
+	}
+	if !r.OK {
+		return nil, fmt.Errorf("getUpdates 返回错误 (%s): %s", resp.Status, r.Description)
+	}
+	return r.Result, nil
+}
+
+// dispatch 解析命令并回复；未授权 chat 与未知命令均忽略或提示
+func (b *Bot) dispatch(ctx context.Context, u Update) {
+	if u.Message == nil || !strings.HasPrefix(u.Message.Text, "/") {
+		return
+	}
+	chatID := fmt.Sprint(u.Message.Chat.ID)
+	if !b.allowed[chatID] {
+		logging.Component("bot").Warn("忽略未授权 chat 的命令", "chat_id", chatID, "text", u.Message.Text)
+		return
+	}
+
+	fields := strings.Fields(u.Message.Text)
+	// 群组中命令可能带 @botname 后缀
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	fn, ok := b.handlers[command]
+
+	var reply string
+	if !ok {
+		reply = "未知命令: " + command
+	} else {
+		reply = b.safeCall(fn, fields[1:])
+	}
+	if reply == "" {
+		return
+	}
+	if err := b.reply(ctx, chatID, u.Message.MessageID, reply); err != nil {
+		logging.Component("bot").Error("回复命令失败", "command", command, "err", err)
+	}
+}
+
+// safeCall 处理函数 panic 时返回错误文本，不影响轮询
+func (b *Bot) safeCall(fn CommandFunc, args []string) (reply string) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.PanicsRecovered.Inc("bot_command")
+			reply = fmt.Sprintf("命令执行出错: %v", r)
+		}
+	}()
+	return fn(args)
+}
+
+func (b *Bot) reply(ctx context.Context, chatID string, replyTo int, text string) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":             chatID,
+		"text":                text,
+		"reply_to_message_id": replyTo,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s%s/sendMessage", b.APIURL, b.Token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	resp, err := b.Client.Do(req)
+	metrics.ObserveRequest(metrics.ServiceTelegram, start, resp, err)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("非 200 返回: %s", resp.Status)
+	}
+	return nil
+}
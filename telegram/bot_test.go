@@ -0,0 +1,166 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeUpdates 假 getUpdates：offset 为 0 时返回 updates，之后返回空；记录收到的 offset 与回复
+type fakeUpdates struct {
+	*httptest.Server
+	mu      sync.Mutex
+	offsets []string
+	replies []map[string]any
+	polled  chan string // 每次 getUpdates 的 offset
+}
+
+func newFakeUpdates(t *testing.T, updates string) *fakeUpdates {
+	f := &fakeUpdates{polled: make(chan string, 100)}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			offset := r.URL.Query().Get("offset")
+			f.mu.Lock()
+			f.offsets = append(f.offsets, offset)
+			f.mu.Unlock()
+			f.polled <- offset
+			if offset == "0" {
+				io.WriteString(w, `{"ok":true,"result":`+updates+`}`)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+			io.WriteString(w, `{"ok":true,"result":[]}`)
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("解析回复失败: %v", err)
+			}
+			f.mu.Lock()
+			f.replies = append(f.replies, body)
+			f.mu.Unlock()
+			io.WriteString(w, `{"ok":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func update(id int, chat int64, text string) map[string]any {
+	return map[string]any{"update_id": id, "message": map[string]any{"message_id": id * 10, "text": text, "chat": map[string]any{"id": chat}}}
+}
+
+func TestBotRun(t *testing.T) {
+	updates, _ := json.Marshal([]map[string]any{
+		update(1, 1, "/status"),
+		update(2, 999, "/status"),           // 未授权 chat
+		update(3, 1, "/Status@MyBot  a  b"), // 群组中的 @botname 后缀
+		update(4, 1, "/nope"),               // 未知命令
+		update(5, 1, "/boom"),               // 处理函数 panic
+		update(6, 1, "hello"),               // 非命令
+		{"update_id": 7},                    // 非消息更新
+	})
+	f := newFakeUpdates(t, string(updates))
+
+	b, err := NewBot("TOKEN", "", []string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.APIURL = f.URL + "/bot"
+	b.PollTimeout = time.Second
+	var gotArgs [][]string
+	b.Handle("/status", func(args []string) string {
+		gotArgs = append(gotArgs, args)
+		return "ok"
+	})
+	b.Handle("/boom", func([]string) string { panic("kaboom") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		b.Run(ctx)
+		close(done)
+	}()
+	// 等待带新 offset 的下一次轮询，说明第一批已处理完
+	timeout := time.After(5 * time.Second)
+	for next := false; !next; {
+		select {
+		case o := <-f.polled:
+			next = o != "0"
+		case <-timeout:
+			t.Fatal("未进行第二次轮询")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ctx 取消后 Run 未返回")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.offsets[0] != "0" || f.offsets[1] != "8" {
+		t.Errorf("offsets = %v, 第二次应为最后一个 update_id + 1", f.offsets)
+	}
+	if len(gotArgs) != 2 || len(gotArgs[1]) != 2 || gotArgs[1][0] != "a" || gotArgs[1][1] != "b" {
+		t.Errorf("handler args = %v", gotArgs)
+	}
+
+	want := []struct {
+		replyTo float64
+		text    string
+	}{
+		{10, "ok"},
+		{30, "ok"},
+		{40, "未知命令: /nope"},
+		{50, "命令执行出错: kaboom"},
+	}
+	if len(f.replies) != len(want) {
+		t.Fatalf("回复 %d 条, want %d: %v", len(f.replies), len(want), f.replies)
+	}
+	for i, w := range want {
+		r := f.replies[i]
+		if r["chat_id"] != "1" || r["reply_to_message_id"] != w.replyTo || r["text"] != w.text {
+			t.Errorf("回复 %d = %v, want %v", i, r, w)
+		}
+	}
+}
+
+func TestBotNoAllowedChats(t *testing.T) {
+	f := newFakeUpdates(t, `[]`)
+	b, err := NewBot("TOKEN", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.APIURL = f.URL + "/bot"
+	called := false
+	b.Handle("/status", func([]string) string { called = true; return "ok" })
+	u := Update{UpdateID: 1}
+	json.Unmarshal([]byte(`{"update_id":1,"message":{"message_id":1,"text":"/status","chat":{"id":1}}}`), &u)
+	b.dispatch(context.Background(), u)
+	if called || len(f.replies) != 0 {
+		t.Error("未配置授权 chat 时应拒绝所有命令")
+	}
+}
+
+func TestBotGetUpdatesError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"ok":false,"description":"Unauthorized"}`)
+	}))
+	defer srv.Close()
+	b, _ := NewBot("TOKEN", "", []string{"1"})
+	b.APIURL = srv.URL + "/bot"
+	if _, err := b.getUpdates(context.Background()); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,380 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"onchain-energe-SRSI/logging"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Priority 出站消息优先级，首次警报先于常规信号发送
+type Priority int
+
+const (
+	PriorityNormal Priority = iota // 常规信号，可合并为摘要
+	PriorityHigh                   // 首次警报，不合并、优先发送
+)
+
+// telegramMaxText Telegram 单条消息长度上限
+const telegramMaxText = 4096
+
+var (
+	ErrOutboxClosed = errors.New("telegram outbox 已关闭")
+	ErrOutboxFull   = errors.New("telegram outbox 队列已满")
+)
+
+// OutboundMessage 待发送的 Telegram 消息
+type OutboundMessage struct {
+	BotToken  string
+	ChatID    string
+	Text      string
+	ParseMode string // 为空表示纯文本，可为 "Markdown"
+	Photo     []byte // 非空时以 sendPhoto 发送，Text 作为图片说明；合并为摘要时只保留第一张图
+	Priority  Priority
+	ReplyTo   int                 // 回复的 message_id，0 表示不回复
+	Record    bool                // 发送成功后写入 savedMessages（参与首次警报判定）
+	OnSent    func(messageID int) // 发送成功后的回调，可为空；合并发送时为摘要消息的 id
+
+	enqueuedAt time.Time
+}
+
+// key 合并摘要时用于判断是否同一目标
+func (m *OutboundMessage) key() string {
+	return fmt.Sprintf("%s|%s|%s|%d", m.BotToken, m.ChatID, m.ParseMode, m.ReplyTo)
+}
+
+// OutboxConfig 出站队列参数
+type OutboxConfig struct {
+	GlobalPerSecond  float64       // 全局每秒上限（Telegram: 30/s）
+	PrivatePerSecond float64       // 私聊每秒上限（Telegram: 1/s）
+	GroupPerMinute   float64       // 群组每分钟上限（Telegram: 20/min）
+	CoalesceWindow   time.Duration // 常规信号合并窗口
+	MaxDigest        int           // 单条摘要最多合并条数
+	QueueSize        int           // 队列容量
+}
+
+// tokenBucket 简单令牌桶，容量为 1，避免突发
+type tokenBucket struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newTokenBucket(perSecond float64) *tokenBucket {
+	if perSecond <= 0 {
+		return &tokenBucket{}
+	}
+	return &tokenBucket{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait 返回距离下一次可发送的等待时间，0 表示可立即发送
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	if now.Before(b.next) {
+		return b.next.Sub(now)
+	}
+	return 0
+}
+
+func (b *tokenBucket) take(now time.Time) {
+	b.next = now.Add(b.interval)
+}
+
+// Outbox 异步出站队列：按 chat 限流、高优先级先发、常规信号合并为摘要，关闭时排空
+type Outbox struct {
+	cfg  OutboxConfig
+	send func(ctx context.Context, m *OutboundMessage) (int, error)
+
+	// ctx 在 Close 超时后取消，中断仍在重试的发送
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	high    []*OutboundMessage
+	normal  []*OutboundMessage
+	global  *tokenBucket
+	chats   map[string]*tokenBucket
+	closing bool
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+// NewOutbox 创建并启动出站队列
+func NewOutbox(cfg OutboxConfig) *Outbox {
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &Outbox{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		global: newTokenBucket(cfg.GlobalPerSecond),
+		chats:  make(map[string]*tokenBucket),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	o.send = sendOutbound
+	go o.run()
+	return o
+}
+
+// Enqueue 非阻塞入队
+func (o *Outbox) Enqueue(m OutboundMessage) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closing {
+		return ErrOutboxClosed
+	}
+	if o.cfg.QueueSize > 0 && len(o.high)+len(o.normal) >= o.cfg.QueueSize {
+		return ErrOutboxFull
+	}
+	m.enqueuedAt = time.Now()
+	if m.Priority == PriorityHigh {
+		o.high = append(o.high, &m)
+	} else {
+		o.normal = append(o.normal, &m)
+	}
+	o.notify()
+	return nil
+}
+
+// Len 当前排队消息数
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.high) + len(o.normal)
+}
+
+// Close 停止接收新消息并排空队列，ctx 到期时放弃剩余消息
+func (o *Outbox) Close(ctx context.Context) error {
+	o.mu.Lock()
+	o.closing = true
+	o.notify()
+	o.mu.Unlock()
+
+	select {
+	case <-o.done:
+		return nil
+	case <-ctx.Done():
+		o.cancel()
+		return fmt.Errorf("outbox 未排空，剩余 %d 条: %w", o.Len(), ctx.Err())
+	}
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+	defer o.cancel()
+	for {
+		batch, wait, empty := o.next(time.Now())
+		if batch != nil {
+			o.deliver(batch)
+			continue
+		}
+
+		o.mu.Lock()
+		finished := o.closing && empty
+		o.mu.Unlock()
+		if finished {
+			return
+		}
+
+		if empty {
+			<-o.wake
+			continue
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-o.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// chatBucket 群组（chat_id 为负数）与私聊使用不同的限速
+func (o *Outbox) chatBucket(chatID string) *tokenBucket {
+	b, ok := o.chats[chatID]
+	if !ok {
+		if strings.HasPrefix(chatID, "-") {
+			b = newTokenBucket(o.cfg.GroupPerMinute / 60)
+		} else {
+			b = newTokenBucket(o.cfg.PrivatePerSecond)
+		}
+		o.chats[chatID] = b
+	}
+	return b
+}
+
+// next 取出下一批可立即发送的消息；否则返回需等待的时长
+func (o *Outbox) next(now time.Time) (batch []*OutboundMessage, wait time.Duration, empty bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.high) == 0 && len(o.normal) == 0 {
+		return nil, 0, true
+	}
+
+	wait = time.Hour
+	ready := func(m *OutboundMessage) bool {
+		w := max(o.global.wait(now), o.chatBucket(m.ChatID).wait(now))
+		if w > 0 {
+			wait = min(wait, w)
+			return false
+		}
+		return true
+	}
+
+	for i, m := range o.high {
+		if ready(m) {
+			o.high = append(o.high[:i], o.high[i+1:]...)
+			o.take(now, m.ChatID)
+			return []*OutboundMessage{m}, 0, false
+		}
+	}
+
+	for i, m := range o.normal {
+		// 关闭时不再等待合并窗口
+		if !o.closing {
+			if w := m.enqueuedAt.Add(o.cfg.CoalesceWindow).Sub(now); w > 0 {
+				wait = min(wait, w)
+				continue
+			}
+		}
+		if !ready(m) {
+			continue
+		}
+		batch = o.collect(i)
+		o.take(now, m.ChatID)
+		return batch, 0, false
+	}
+	return nil, wait, false
+}
+
+// collect 以 normal[i] 为首，合并同一目标的后续常规消息；含图片时按图片说明长度上限合并，只附第一张图
+func (o *Outbox) collect(i int) []*OutboundMessage {
+	first := o.normal[i]
+	batch := []*OutboundMessage{first}
+	hasPhoto := first.Photo != nil
+	size := utf8.RuneCountInString(first.Text)
+	rest := append([]*OutboundMessage{}, o.normal[:i]...)
+	for _, m := range o.normal[i+1:] {
+		n := utf8.RuneCountInString(m.Text) + 2
+		limit := telegramMaxText - 64
+		if hasPhoto || m.Photo != nil {
+			limit = telegramMaxCaption - 32 // 预留摘要标题
+		}
+		if m.key() == first.key() && len(batch) < max(o.cfg.MaxDigest, 1) && size+n < limit {
+			batch = append(batch, m)
+			size += n
+			hasPhoto = hasPhoto || m.Photo != nil
+			continue
+		}
+		rest = append(rest, m)
+	}
+	o.normal = rest
+	return batch
+}
+
+func (o *Outbox) take(now time.Time, chatID string) {
+	o.global.take(now)
+	o.chatBucket(chatID).take(now)
+}
+
+func (o *Outbox) deliver(batch []*OutboundMessage) {
+	m := batch[0]
+	if len(batch) > 1 {
+		texts := make([]string, len(batch))
+		for i, b := range batch {
+			texts[i] = b.Text
+		}
+		digest := *m
+		digest.Text = fmt.Sprintf("📦 %d 条信号\n\n%s", len(batch), strings.Join(texts, "\n\n"))
+		for _, b := range batch {
+			if b.Photo != nil {
+				digest.Photo = b.Photo
+				break
+			}
+		}
+		m = &digest
+	}
+
+	messageID, err := o.send(o.ctx, m)
+	if err != nil {
+		logging.Component("outbox").Error("发送失败", "chat_id", m.ChatID, "messages", len(batch), "err", err)
+		return
+	}
+	for _, b := range batch {
+		if b.Record {
+			AddMessage(SavedMessage{Text: b.Text, Timestamp: time.Now()})
+		}
+		if b.OnSent != nil {
+			b.OnSent(messageID)
+		}
+	}
+}
+
+// sendOutbound 根据是否带图片选择 sendPhoto 或 sendMessage
+func sendOutbound(ctx context.Context, m *OutboundMessage) (int, error) {
+	if m.Photo != nil {
+		return postPhoto(ctx, m.BotToken, m.ChatID, m.Text, m.ParseMode, m.Photo, m.ReplyTo)
+	}
+	return postMessage(ctx, m.BotToken, m.ChatID, m.Text, m.ParseMode, m.ReplyTo)
+}
+
+var defaultOutbox struct {
+	sync.RWMutex
+	o *Outbox
+}
+
+// StartOutbox 启动全局出站队列，Enqueue 将使用它
+func StartOutbox(cfg OutboxConfig) *Outbox {
+	o := NewOutbox(cfg)
+	defaultOutbox.Lock()
+	defaultOutbox.o = o
+	defaultOutbox.Unlock()
+	return o
+}
+
+// Enqueue 将消息放入全局出站队列；未启动队列时退化为后台直接发送
+func Enqueue(m OutboundMessage) error {
+	defaultOutbox.RLock()
+	o := defaultOutbox.o
+	defaultOutbox.RUnlock()
+	if o != nil {
+		return o.Enqueue(m)
+	}
+
+	go func() {
+		messageID, err := sendOutbound(context.Background(), &m)
+		if err != nil {
+			logging.Component("outbox").Error("直接发送失败", "chat_id", m.ChatID, "err", err)
+			return
+		}
+		if m.Record {
+			AddMessage(SavedMessage{Text: m.Text, Timestamp: time.Now()})
+		}
+		if m.OnSent != nil {
+			m.OnSent(messageID)
+		}
+	}()
+	return nil
+}
+
+// CloseOutbox 排空并关闭全局出站队列
+func CloseOutbox(ctx context.Context) error {
+	defaultOutbox.RLock()
+	o := defaultOutbox.o
+	defaultOutbox.RUnlock()
+	if o == nil {
+		return nil
+	}
+	return o.Close(ctx)
+}
@@ -0,0 +1,249 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCollectCoalescesPhotos(t *testing.T) {
+	msg := func(text string, photo []byte) *OutboundMessage {
+		return &OutboundMessage{BotToken: "T", ChatID: "1", Text: text, Photo: photo}
+	}
+	tests := []struct {
+		name      string
+		normal    []*OutboundMessage
+		wantBatch int
+		wantRest  int
+		wantPhoto string
+	}{
+		{"图片与文字合并，只附第一张图", []*OutboundMessage{msg("a", nil), msg("b", []byte("p1")), msg("c", []byte("p2"))}, 3, 0, "p1"},
+		{"纯文字", []*OutboundMessage{msg("a", nil), msg("b", nil)}, 2, 0, ""},
+		{"超过图片说明上限的留到下一批",
+			[]*OutboundMessage{msg(strings.Repeat("x", 600), []byte("p1")), msg(strings.Repeat("y", 600), []byte("p2"))}, 1, 1, "p1"},
+		{"不同 chat 不合并", []*OutboundMessage{msg("a", []byte("p1")), {BotToken: "T", ChatID: "2", Text: "b"}}, 1, 1, "p1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *OutboundMessage
+			o := &Outbox{
+				cfg:    OutboxConfig{MaxDigest: 10},
+				ctx:    context.Background(),
+				normal: tt.normal,
+				send: func(_ context.Context, m *OutboundMessage) (int, error) {
+					sent = m
+					return 1, nil
+				},
+			}
+			batch := o.collect(0)
+			if len(batch) != tt.wantBatch || len(o.normal) != tt.wantRest {
+				t.Fatalf("batch = %d, rest = %d, want %d, %d", len(batch), len(o.normal), tt.wantBatch, tt.wantRest)
+			}
+			o.deliver(batch)
+			if string(sent.Photo) != tt.wantPhoto {
+				t.Errorf("photo = %q, want %q", sent.Photo, tt.wantPhoto)
+			}
+			if len(batch) > 1 && !strings.HasPrefix(sent.Text, "📦") {
+				t.Errorf("未合并为摘要: %q", sent.Text)
+			}
+		})
+	}
+}
+
+// testOutbox 与 NewOutbox 相同但不启动 run，便于用注入的 now 调用 next
+func testOutbox(cfg OutboxConfig) *Outbox {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Outbox{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		global: newTokenBucket(cfg.GlobalPerSecond),
+		chats:  make(map[string]*tokenBucket),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+// push 直接入队并指定入队时间
+func push(o *Outbox, chatID, text string, p Priority, at time.Time) {
+	m := &OutboundMessage{BotToken: "T", ChatID: chatID, Text: text, Priority: p, enqueuedAt: at}
+	if p == PriorityHigh {
+		o.high = append(o.high, m)
+	} else {
+		o.normal = append(o.normal, m)
+	}
+}
+
+func texts(batch []*OutboundMessage) string {
+	var s []string
+	for _, m := range batch {
+		s = append(s, m.Text)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestNextPacing(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := testOutbox(OutboxConfig{GlobalPerSecond: 10, PrivatePerSecond: 1, GroupPerMinute: 20})
+	push(o, "1", "p1", PriorityHigh, t0)
+	push(o, "1", "p2", PriorityHigh, t0)
+	push(o, "-100", "g1", PriorityHigh, t0)
+	push(o, "-100", "g2", PriorityHigh, t0)
+	push(o, "2", "q1", PriorityHigh, t0)
+
+	ms := func(n int) time.Time { return t0.Add(time.Duration(n) * time.Millisecond) }
+	steps := []struct {
+		at       time.Time
+		want     string
+		wantWait time.Duration
+	}{
+		{ms(0), "p1", 0},
+		{ms(0), "", 100 * time.Millisecond},   // 全局 10/s
+		{ms(100), "g1", 0},                    // chat 1 私聊 1/s 未到，先发其他 chat
+		{ms(200), "q1", 0},                    // 群组 20/min 即 3s 一条
+		{ms(300), "", 700 * time.Millisecond}, // 距 chat 1 可发还有 700ms
+		{ms(1000), "p2", 0},
+		{ms(1100), "", 2000 * time.Millisecond}, // 群组间隔 3s
+		{ms(3100), "g2", 0},
+	}
+	for i, s := range steps {
+		batch, wait, empty := o.next(s.at)
+		if empty {
+			t.Fatalf("第 %d 步队列不应为空", i)
+		}
+		if got := texts(batch); got != s.want || (s.want == "" && wait != s.wantWait) {
+			t.Fatalf("第 %d 步 next = %q (wait %v), want %q (wait %v)", i, got, wait, s.want, s.wantWait)
+		}
+	}
+	if _, _, empty := o.next(ms(10000)); !empty {
+		t.Error("全部发出后队列应为空")
+	}
+}
+
+func TestNextHighPriorityFirst(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := testOutbox(OutboxConfig{MaxDigest: 10})
+	push(o, "1", "n1", PriorityNormal, t0.Add(-time.Minute))
+	push(o, "1", "h1", PriorityHigh, t0)
+	push(o, "1", "n2", PriorityNormal, t0.Add(-time.Minute))
+	push(o, "1", "h2", PriorityHigh, t0)
+	var order []string
+	for {
+		batch, _, empty := o.next(t0)
+		if empty {
+			break
+		}
+		order = append(order, texts(batch))
+	}
+	// 首次警报逐条发送且先于常规信号，常规信号合并为一条摘要
+	if got := strings.Join(order, " | "); got != "h1 | h2 | n1,n2" {
+		t.Errorf("发送顺序 = %s", got)
+	}
+}
+
+func TestNextCoalesceWindow(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := testOutbox(OutboxConfig{CoalesceWindow: 3 * time.Second, MaxDigest: 2})
+	push(o, "1", "a", PriorityNormal, t0)
+	push(o, "1", "b", PriorityNormal, t0.Add(time.Second))
+	push(o, "1", "c", PriorityNormal, t0.Add(2*time.Second))
+	push(o, "2", "x", PriorityNormal, t0.Add(time.Second))
+
+	if batch, wait, _ := o.next(t0.Add(2 * time.Second)); batch != nil || wait != time.Second {
+		t.Fatalf("窗口内不应发送: batch %q wait %v", texts(batch), wait)
+	}
+	batch, _, _ := o.next(t0.Add(3 * time.Second))
+	if got := texts(batch); got != "a,b" {
+		t.Errorf("窗口到期后合并 = %q, want a,b（MaxDigest 2，不同 chat 不合并）", got)
+	}
+	// 关闭时不再等待合并窗口
+	o.closing = true
+	batch, _, _ = o.next(t0.Add(3 * time.Second))
+	if got := texts(batch); got != "c" {
+		t.Errorf("关闭时 next = %q, want c", got)
+	}
+	if batch, _, _ = o.next(t0.Add(3 * time.Second)); texts(batch) != "x" {
+		t.Errorf("关闭时 next = %q, want x", texts(batch))
+	}
+}
+
+// startTestOutbox 以 send 替代真实发送并启动
+func startTestOutbox(cfg OutboxConfig, send func(context.Context, *OutboundMessage) (int, error)) *Outbox {
+	o := testOutbox(cfg)
+	o.send = send
+	go o.run()
+	return o
+}
+
+func TestCloseDrains(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	o := startTestOutbox(OutboxConfig{GlobalPerSecond: 1000, PrivatePerSecond: 1000, CoalesceWindow: time.Hour, MaxDigest: 10, QueueSize: 10},
+		func(_ context.Context, m *OutboundMessage) (int, error) {
+			mu.Lock()
+			sent = append(sent, m.ChatID)
+			mu.Unlock()
+			return 7, nil
+		})
+	var ids []int
+	for _, chat := range []string{"1", "2", "3"} {
+		if err := o.Enqueue(OutboundMessage{ChatID: chat, Text: chat, OnSent: func(id int) {
+			mu.Lock()
+			ids = append(ids, id)
+			mu.Unlock()
+		}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := o.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 3 || len(ids) != 3 || ids[0] != 7 {
+		t.Errorf("关闭时应排空队列（不等合并窗口）: sent %v, ids %v", sent, ids)
+	}
+	if err := o.Enqueue(OutboundMessage{ChatID: "1"}); !errors.Is(err, ErrOutboxClosed) {
+		t.Errorf("关闭后入队 err = %v", err)
+	}
+}
+
+func TestCloseTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	var once sync.Once
+	o := startTestOutbox(OutboxConfig{MaxDigest: 1, QueueSize: 10},
+		func(ctx context.Context, m *OutboundMessage) (int, error) {
+			<-ctx.Done() // 模拟一直重试的发送
+			once.Do(func() { close(cancelled) })
+			return 0, ctx.Err()
+		})
+	o.Enqueue(OutboundMessage{ChatID: "1", Text: "a", Priority: PriorityHigh})
+	o.Enqueue(OutboundMessage{ChatID: "1", Text: "b", Priority: PriorityHigh})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := o.Close(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close err = %v, want DeadlineExceeded", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close 超时后应取消进行中的发送")
+	}
+}
+
+func TestEnqueueFull(t *testing.T) {
+	o := testOutbox(OutboxConfig{QueueSize: 1})
+	if err := o.Enqueue(OutboundMessage{ChatID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Enqueue(OutboundMessage{ChatID: "1"}); !errors.Is(err, ErrOutboxFull) {
+		t.Errorf("err = %v, want ErrOutboxFull", err)
+	}
+}
@@ -0,0 +1,107 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
+	"time"
+)
+
+// telegramMaxCaption 图片说明长度上限
+const telegramMaxCaption = 1024
+
+// SendPhoto 以 multipart 上传 PNG 并附带说明文字，包含重试；成功后写入 savedMessages
+func SendPhoto(ctx context.Context, botToken, chatID, caption, parseMode string, photo []byte) error {
+	if _, err := postPhoto(ctx, botToken, chatID, caption, parseMode, photo, 0); err != nil {
+		return err
+	}
+	AddMessage(SavedMessage{
+		Text:      caption,
+		Timestamp: time.Now(),
+	})
+	return nil
+}
+
+func postPhoto(ctx context.Context, botToken, chatID, caption, parseMode string, photo []byte, replyTo int) (int, error) {
+	if len([]rune(caption)) > telegramMaxCaption {
+		caption = string([]rune(caption)[:telegramMaxCaption])
+	}
+
+	client := &http.Client{
+		Transport: httpTransport,
+		Timeout:   30 * time.Second, // 图片上传较慢
+	}
+	url := fmt.Sprintf("%s%s/sendPhoto", telegramAPIURL, botToken)
+
+	// 每次重试都需要新的 body，先生成一份完整的 multipart 内容
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fields := map[string]string{"chat_id": chatID, "caption": caption}
+	if parseMode != "" {
+		fields["parse_mode"] = parseMode
+	}
+	if replyTo != 0 {
+		fields["reply_to_message_id"] = fmt.Sprint(replyTo)
+		fields["allow_sending_without_reply"] = "true"
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return 0, fmt.Errorf("构造 multipart 失败: %w", err)
+		}
+	}
+	part, err := w.CreateFormFile("photo", "chart.png")
+	if err != nil {
+		return 0, fmt.Errorf("构造 multipart 失败: %w", err)
+	}
+	if _, err := part.Write(photo); err != nil {
+		return 0, fmt.Errorf("写入图片失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return 0, fmt.Errorf("构造 multipart 失败: %w", err)
+	}
+
+	const maxRetries = 3
+	backoff := 1 * time.Second
+	var lastErr error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		var retryAfter time.Duration
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return 0, fmt.Errorf("构造请求失败: %w", err)
+		}
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		if attempt > 1 {
+			metrics.Retries.Inc(metrics.ServiceTelegram)
+		}
+		start := time.Now()
+		resp, err := client.Do(req)
+		metrics.ObserveRequest(metrics.ServiceTelegram, start, resp, err)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to send photo (attempt %d/%d): %w", attempt, maxRetries, err)
+		} else {
+			if resp.StatusCode == http.StatusOK {
+				messageID := parseMessageID(resp.Body)
+				resp.Body.Close()
+				return messageID, nil
+			}
+			lastErr = fmt.Errorf("received non-200 response (attempt %d/%d): %s", attempt, maxRetries, resp.Status)
+			if resp.StatusCode == http.StatusTooManyRequests {
+				retryAfter = parseRetryAfter(resp.Body)
+			}
+			resp.Body.Close()
+		}
+
+		delay := max(backoff, retryAfter)
+		logging.Component("telegram").Warn("图片发送失败，稍后重试", "chat_id", chatID, "retry_in", delay,
+			"attempt", attempt, "max_attempts", maxRetries, "err", lastErr)
+		if err := sleepContext(ctx, delay); err != nil {
+			return 0, fmt.Errorf("发送图片中断: %w", lastErr)
+		}
+		backoff *= 2
+	}
+	return 0, fmt.Errorf("多次发送图片失败: %w", lastErr)
+}
@@ -0,0 +1,136 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeBotAPI 本地假 Bot API，记录最后一次请求
+type fakeBotAPI struct {
+	*httptest.Server
+	path      string
+	json      map[string]any
+	form      map[string]string
+	photo     []byte
+	failFirst atomic.Int32 // 前几次返回 429
+}
+
+func newFakeBotAPI(t *testing.T) *fakeBotAPI {
+	f := &fakeBotAPI{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.failFirst.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"ok":false,"parameters":{"retry_after":0}}`)
+			return
+		}
+		f.path = r.URL.Path
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("解析 multipart 失败: %v", err)
+			}
+			f.form = map[string]string{}
+			for k, v := range r.MultipartForm.Value {
+				f.form[k] = v[0]
+			}
+			file, _, err := r.FormFile("photo")
+			if err != nil {
+				t.Errorf("缺少 photo: %v", err)
+			} else {
+				f.photo, _ = io.ReadAll(file)
+			}
+		} else if err := json.NewDecoder(r.Body).Decode(&f.json); err != nil {
+			t.Errorf("解析 JSON 失败: %v", err)
+		}
+		io.WriteString(w, `{"ok":true,"result":{"message_id":42}}`)
+	}))
+	t.Cleanup(f.Close)
+	SetAPIURL(f.URL + "/bot")
+	if err := SetProxy(""); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestPostMessage(t *testing.T) {
+	f := newFakeBotAPI(t)
+	id, err := postMessage(context.Background(), "TOKEN", "100", "*hi*", "Markdown", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Errorf("message_id = %d, want 42", id)
+	}
+	if f.path != "/botTOKEN/sendMessage" {
+		t.Errorf("path = %s", f.path)
+	}
+	want := map[string]any{"chat_id": "100", "text": "*hi*", "parse_mode": "Markdown",
+		"reply_to_message_id": float64(7), "allow_sending_without_reply": true}
+	for k, v := range want {
+		if f.json[k] != v {
+			t.Errorf("%s = %v, want %v", k, f.json[k], v)
+		}
+	}
+}
+
+func TestPostMessageRetriesOn429(t *testing.T) {
+	f := newFakeBotAPI(t)
+	f.failFirst.Store(1)
+	if _, err := postMessage(context.Background(), "TOKEN", "100", "hi", "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if f.json["text"] != "hi" {
+		t.Errorf("重试后未发送: %v", f.json)
+	}
+}
+
+func TestPostPhoto(t *testing.T) {
+	f := newFakeBotAPI(t)
+	png := []byte("\x89PNG fake")
+	caption := strings.Repeat("长", telegramMaxCaption+10)
+	id, err := postPhoto(context.Background(), "TOKEN", "-100", caption, "Markdown", png, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 || f.path != "/botTOKEN/sendPhoto" {
+		t.Errorf("id = %d, path = %s", id, f.path)
+	}
+	if string(f.photo) != string(png) {
+		t.Errorf("photo = %q", f.photo)
+	}
+	if n := len([]rune(f.form["caption"])); n != telegramMaxCaption {
+		t.Errorf("caption 长度 = %d, want %d", n, telegramMaxCaption)
+	}
+	if f.form["chat_id"] != "-100" || f.form["parse_mode"] != "Markdown" || f.form["reply_to_message_id"] != "9" {
+		t.Errorf("form = %v", f.form)
+	}
+}
+
+// 配置代理时请求经由代理发出
+func TestSetProxy(t *testing.T) {
+	var proxied atomic.Bool
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(true)
+		io.WriteString(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer proxy.Close()
+	SetAPIURL("http://telegram.invalid/bot")
+	if err := SetProxy(proxy.URL); err != nil {
+		t.Fatal(err)
+	}
+	defer SetProxy("")
+	if _, err := postMessage(context.Background(), "TOKEN", "1", "hi", "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if !proxied.Load() {
+		t.Error("请求未经过代理")
+	}
+	if err := SetProxy("://bad"); err == nil {
+		t.Error("无效代理地址应返回错误")
+	}
+}
@@ -0,0 +1,233 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/types"
+	"sync"
+	"time"
+)
+
+// Checkpoints 信号触发后的复查时间点
+var Checkpoints = []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour, 4 * time.Hour}
+
+// maxRecords 内存中保留的信号记录上限
+const maxRecords = 1000
+
+// PriceFunc 查询信号代币的当前价格
+type PriceFunc func(ctx context.Context, sig types.Signal) (float64, error)
+
+// Outcome 单个检查点的结果
+type Outcome struct {
+	After  string    `json:"after"` // 如 "5m0s"
+	Price  float64   `json:"price"`
+	PnLPct float64   `json:"pnl_pct"` // 相对入场价的涨跌幅（%）
+	Time   time.Time `json:"time"`
+	Err    string    `json:"error,omitempty"`
+}
+
+// Record 一条信号及其各检查点结果
+type Record struct {
+	Signal   types.Signal `json:"signal"`
+	Outcomes []Outcome    `json:"outcomes"`
+}
+
+// CheckpointStats 某策略在某检查点的统计，PnL > 0 记为命中
+type CheckpointStats struct {
+	After   string  `json:"after"`
+	Count   int     `json:"count"`
+	Hits    int     `json:"hits"`
+	HitRate float64 `json:"hit_rate"`
+	AvgPnL  float64 `json:"avg_pnl_pct"`
+
+	sumPnL float64
+}
+
+// Tracker 记录买入信号的入场价，并在各检查点重新采样价格
+type Tracker struct {
+	price     PriceFunc
+	onOutcome func(Record, Outcome)
+
+	mu      sync.Mutex
+	records map[string]*Record
+	order   []string // 信号 ID，按时间先后
+	stats   map[string]map[time.Duration]*CheckpointStats
+	timers  map[string][]*time.Timer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New 创建跟踪器；onOutcome 在每个检查点采样完成后调用，可为空
+func New(price PriceFunc, onOutcome func(Record, Outcome)) *Tracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Tracker{
+		price:     price,
+		onOutcome: onOutcome,
+		records:   make(map[string]*Record),
+		stats:     make(map[string]map[time.Duration]*CheckpointStats),
+		timers:    make(map[string][]*time.Timer),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Track 开始跟踪一条信号，入场价为信号中的收盘价
+func (t *Tracker) Track(sig types.Signal) {
+	if sig.ID == "" || sig.Price <= 0 {
+		return
+	}
+	sig.Chart = nil
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.ctx.Err() != nil {
+		return
+	}
+	t.records[sig.ID] = &Record{Signal: sig}
+	t.order = append(t.order, sig.ID)
+	if len(t.order) > maxRecords {
+		old := t.order[0]
+		t.order = t.order[1:]
+		delete(t.records, old)
+		for _, tm := range t.timers[old] {
+			tm.Stop()
+		}
+		delete(t.timers, old)
+	}
+
+	for _, after := range Checkpoints {
+		delay := time.Until(sig.Time.Add(after))
+		t.timers[sig.ID] = append(t.timers[sig.ID], time.AfterFunc(delay, func() {
+			t.check(sig, after)
+		}))
+	}
+}
+
+func (t *Tracker) check(sig types.Signal, after time.Duration) {
+	ctx, cancel := context.WithTimeout(t.ctx, 30*time.Second)
+	defer cancel()
+
+	o := Outcome{After: after.String(), Time: time.Now()}
+	price, err := t.price(ctx, sig)
+	if err != nil || price <= 0 {
+		if err == nil {
+			err = fmt.Errorf("价格无效: %v", price)
+		}
+		o.Err = err.Error()
+		logging.Component("tracker").Warn("信号结果采样失败", logging.KeySymbol, sig.Symbol,
+			logging.KeyAddress, sig.Address, "after", after, "err", err)
+	} else {
+		o.Price = price
+		o.PnLPct = (price - sig.Price) / sig.Price * 100
+		if sig.Short {
+			o.PnLPct = -o.PnLPct
+		}
+	}
+
+	t.mu.Lock()
+	rec, ok := t.records[sig.ID]
+	if !ok || t.ctx.Err() != nil {
+		t.mu.Unlock()
+		return
+	}
+	rec.Outcomes = append(rec.Outcomes, o)
+	if o.Err == "" {
+		t.addStats(sig.Strategy, after, o.PnLPct)
+	}
+	snapshot := Record{Signal: rec.Signal, Outcomes: append([]Outcome(nil), rec.Outcomes...)}
+	t.mu.Unlock()
+
+	if t.onOutcome != nil && o.Err == "" {
+		t.onOutcome(snapshot, o)
+	}
+}
+
+// addStats 调用方需持有 t.mu
+func (t *Tracker) addStats(strategy string, after time.Duration, pnl float64) {
+	byAfter, ok := t.stats[strategy]
+	if !ok {
+		byAfter = make(map[time.Duration]*CheckpointStats)
+		t.stats[strategy] = byAfter
+	}
+	s, ok := byAfter[after]
+	if !ok {
+		s = &CheckpointStats{After: after.String()}
+		byAfter[after] = s
+	}
+	s.Count++
+	if pnl > 0 {
+		s.Hits++
+	}
+	s.sumPnL += pnl
+	s.HitRate = float64(s.Hits) / float64(s.Count)
+	s.AvgPnL = s.sumPnL / float64(s.Count)
+}
+
+// Stats 按策略返回各检查点统计，检查点按时间先后排列
+func (t *Tracker) Stats() map[string][]CheckpointStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make(map[string][]CheckpointStats, len(t.stats))
+	for strategy, byAfter := range t.stats {
+		for _, after := range Checkpoints {
+			if s, ok := byAfter[after]; ok {
+				res[strategy] = append(res[strategy], *s)
+			}
+		}
+	}
+	return res
+}
+
+// Recent 返回最近 n 条记录，最新在前
+func (t *Tracker) Recent(n int) []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n = min(n, len(t.order))
+	res := make([]Record, 0, n)
+	for i := len(t.order) - 1; i >= len(t.order)-n; i-- {
+		rec := t.records[t.order[i]]
+		res = append(res, Record{Signal: rec.Signal, Outcomes: append([]Outcome(nil), rec.Outcomes...)})
+	}
+	return res
+}
+
+// Stop 取消所有未到期的检查点
+func (t *Tracker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cancel()
+	for _, timers := range t.timers {
+		for _, tm := range timers {
+			tm.Stop()
+		}
+	}
+	t.timers = make(map[string][]*time.Timer)
+}
+
+// FormatOutcome 跟进消息文本
+func FormatOutcome(rec Record, o Outcome) string {
+	icon := "📈"
+	if o.PnLPct < 0 {
+		icon = "📉"
+	}
+	return fmt.Sprintf("%s %s +%s\n%.6g → %.6g (%+.2f%%)",
+		icon, rec.Signal.Symbol, shortDuration(o.After), rec.Signal.Price, o.Price, o.PnLPct)
+}
+
+// shortDuration 把 "5m0s"、"1h0m0s" 简化为 "5m"、"1h"
+func shortDuration(s string) string {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return s
+	}
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return s
+	}
+}
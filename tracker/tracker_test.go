@@ -0,0 +1,135 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"onchain-energe-SRSI/types"
+	"testing"
+	"time"
+)
+
+// fixedPrice 按信号 ID 返回价格，未配置的返回错误
+func fixedPrice(prices map[string]float64) PriceFunc {
+	return func(_ context.Context, sig types.Signal) (float64, error) {
+		p, ok := prices[sig.ID]
+		if !ok {
+			return 0, errors.New("no price")
+		}
+		return p, nil
+	}
+}
+
+func TestCheckPnL(t *testing.T) {
+	tests := []struct {
+		name  string
+		short bool
+		price float64
+		want  float64
+	}{
+		{"多单上涨", false, 110, 10},
+		{"多单下跌", false, 95, -5},
+		{"空单下跌为正收益", true, 90, 10},
+		{"空单上涨为负收益", true, 105, -5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Outcome
+			tr := New(fixedPrice(map[string]float64{"s": tt.price}), func(_ Record, o Outcome) { got = append(got, o) })
+			defer tr.Stop()
+			sig := types.Signal{ID: "s", Strategy: "cascade", Short: tt.short, Price: 100, Time: time.Now().Add(time.Hour)}
+			tr.Track(sig)
+			tr.check(sig, 5*time.Minute)
+			if len(got) != 1 || math.Abs(got[0].PnLPct-tt.want) > 1e-9 {
+				t.Fatalf("outcomes = %+v, want PnL %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	prices := map[string]float64{"a": 110, "b": 95, "c": 103}
+	var called int
+	tr := New(fixedPrice(prices), func(Record, Outcome) { called++ })
+	defer tr.Stop()
+	future := time.Now().Add(time.Hour)
+	for _, id := range []string{"a", "b", "c", "bad"} {
+		sig := types.Signal{ID: id, Strategy: "cascade", Price: 100, Time: future}
+		tr.Track(sig)
+		tr.check(sig, 5*time.Minute)
+	}
+
+	stats := tr.Stats()["cascade"]
+	if len(stats) != 1 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	s := stats[0]
+	// 采样失败的 bad 不计入统计，也不触发回调
+	if s.Count != 3 || s.Hits != 2 || math.Abs(s.HitRate-2.0/3) > 1e-9 || math.Abs(s.AvgPnL-8.0/3) > 1e-9 {
+		t.Errorf("stats = %+v, want count 3 hits 2 avg 2.67", s)
+	}
+	if called != 3 {
+		t.Errorf("onOutcome 调用 %d 次, want 3", called)
+	}
+	// 失败的采样仍记录在信号结果里
+	for _, rec := range tr.Recent(4) {
+		if rec.Signal.ID == "bad" && (len(rec.Outcomes) != 1 || rec.Outcomes[0].Err == "") {
+			t.Errorf("bad outcomes = %+v", rec.Outcomes)
+		}
+	}
+}
+
+func TestCheckpointsFire(t *testing.T) {
+	done := make(chan Outcome, len(Checkpoints))
+	tr := New(fixedPrice(map[string]float64{"s": 120}), func(_ Record, o Outcome) { done <- o })
+	defer tr.Stop()
+	// 信号时间在过去，所有检查点立即到期
+	tr.Track(types.Signal{ID: "s", Strategy: "cascade", Price: 100, Time: time.Now().Add(-24 * time.Hour)})
+	for range Checkpoints {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("检查点未触发")
+		}
+	}
+	if rec := tr.Recent(1); len(rec) != 1 || len(rec[0].Outcomes) != len(Checkpoints) {
+		t.Errorf("recent = %+v", rec)
+	}
+}
+
+func TestRecentAndEviction(t *testing.T) {
+	tr := New(fixedPrice(nil), nil)
+	defer tr.Stop()
+	future := time.Now().Add(time.Hour)
+	for i := range maxRecords {
+		tr.Track(types.Signal{ID: fmt.Sprint(i), Price: 1, Time: future})
+	}
+	first := tr.timers["0"]
+	tr.Track(types.Signal{ID: "new", Price: 1, Time: future})
+
+	if _, ok := tr.records["0"]; ok || len(tr.records) != maxRecords {
+		t.Errorf("超出上限应淘汰最早的记录: records %d", len(tr.records))
+	}
+	if _, ok := tr.timers["0"]; ok {
+		t.Error("淘汰的记录应删除检查点")
+	}
+	for _, tm := range first {
+		if tm.Stop() {
+			t.Error("淘汰的记录的检查点应已停止")
+		}
+	}
+
+	recent := tr.Recent(3)
+	var ids []string
+	for _, rec := range recent {
+		ids = append(ids, rec.Signal.ID)
+	}
+	want := []string{"new", fmt.Sprint(maxRecords - 1), fmt.Sprint(maxRecords - 2)}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("Recent = %v, want %v（最新在前）", ids, want)
+	}
+	if n := len(tr.Recent(maxRecords + 10)); n != maxRecords {
+		t.Errorf("Recent 超过记录数时返回 %d 条", n)
+	}
+}
@@ -16,4 +16,206 @@ type Config struct {
 	OneAggregate     string `json:"1_aggregate"`
 	FiveAggregate    string `json:"5_aggregate"`
 	FifteenAggregate string `json:"15_aggregate"`
+
+	TelegramQueue  TelegramQueueConfig `json:"telegram_queue"`
+	TelegramAPIURL string              `json:"telegram_api_url"` // 默认官方地址，可指向本地假服务
+	EnableCommands bool                `json:"enable_commands"`  // 是否启用 getUpdates 命令轮询
+	CommandChatIDs []string            `json:"command_chat_ids"` // 允许下发命令的 chat，默认仅 chatId
+	DisableCharts  bool                `json:"disable_charts"`   // 信号不附带 K 线图
+	EnableShort    bool                `json:"enable_short"`     // 同时评估空头级联（BEARMACD / XSELL）
+
+	HTTP               HTTPConfig   `json:"http"`
+	ShutdownTimeoutSec int          `json:"shutdown_timeout_sec"` // 退出时等待扫描与发送完成的上限
+	ScanTimeoutSec     int          `json:"scan_timeout_sec"`     // 单次扫描期限，默认 55 秒，在下一次触发前结束
+	TokenTimeoutSec    int          `json:"token_timeout_sec"`    // 单个代币分析期限
+	Settle             SettleConfig `json:"settle"`
+	Health             HealthConfig `json:"health"`
+	Log                LogConfig    `json:"log"`
+	Paper              PaperConfig  `json:"paper"`
+	Exit               ExitConfig   `json:"exit"`
+	Perp               PerpConfig   `json:"perp"`
+	Execution          ExecConfig   `json:"execution"`
+	Gates              GateConfig   `json:"gates"`
+	Flow               FlowConfig   `json:"flow"`
+	Holders            HolderConfig `json:"holders"`
+
+	Filters FilterConfig `json:"filters"` // Axiom 榜单过滤条件，可通过管理接口在运行时修改
+	Admin   AdminConfig  `json:"admin"`
+
+	Notifiers []NotifierConfig `json:"notifiers"` // 额外通知渠道，内置 telegram 与 telegram_alert
+	Routes    []RouteConfig    `json:"routes"`    // 为空时：常规信号与跟进 -> telegram，首次警报 -> telegram_alert
+}
+
+// NotifierConfig 通知渠道配置
+type NotifierConfig struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // telegram / discord / slack / webhook / stdout
+	URL      string `json:"url"`
+	Secret   string `json:"secret"` // webhook HMAC 签名密钥
+	BotToken string `json:"bot_token"`
+	ChatID   string `json:"chat_id"`
+}
+
+// RouteConfig 路由规则，条件字段为空表示不限
+type RouteConfig struct {
+	Channels   []string `json:"channels"`
+	Strategies []string `json:"strategies"` // cascade / cascade_short，可将空头信号发往单独的 chat
+	Chains     []string `json:"chains"`
+	Tiers      []string `json:"tiers"` // signal / first / outcome / exit
+}
+
+// TelegramQueueConfig Telegram 出站队列配置
+type TelegramQueueConfig struct {
+	GlobalPerSecond  float64 `json:"global_per_second"`  // 全局每秒上限
+	PrivatePerSecond float64 `json:"private_per_second"` // 私聊每秒上限
+	GroupPerMinute   float64 `json:"group_per_minute"`   // 群组每分钟上限
+	CoalesceMs       int     `json:"coalesce_ms"`        // 常规信号合并窗口（毫秒）
+	MaxDigest        int     `json:"max_digest"`         // 单条摘要最多合并条数
+	QueueSize        int     `json:"queue_size"`         // 队列容量
+}
+
+// FilterConfig Axiom 榜单过滤条件，未填写的字段取默认值，下限填 0 表示不限
+type FilterConfig struct {
+	MinMarketCapSol float64 `json:"min_market_cap_sol"`
+	MinHolders      int     `json:"min_holders"`
+	MinLiquiditySol float64 `json:"min_liquidity_sol"`
+	MaxTop10Holders float64 `json:"max_top10_holders"` // 前十持仓占比上限（%）
+	MinBuyCount     int     `json:"min_buy_count"`
+}
+
+// AdminConfig 管理接口配置，Token 与 HMACSecret 均为空时管理接口关闭
+type AdminConfig struct {
+	Token          string   `json:"token"`           // Authorization: Bearer <token>
+	HMACSecret     string   `json:"hmac_secret"`     // X-Signature 签名密钥
+	AllowedOrigins []string `json:"allowed_origins"` // 管理接口允许的跨域来源
+	AuditLog       string   `json:"audit_log"`       // 审计日志路径，默认 DataDir/admin_audit.log
+}
+
+// SettleConfig 1 分钟收盘后等待接口出新 K 线的时长
+type SettleConfig struct {
+	InitialMs int  `json:"initial_ms"` // 默认 10000
+	MinMs     int  `json:"min_ms"`     // 默认 2000
+	MaxMs     int  `json:"max_ms"`     // 默认 30000，超过后不再等待
+	Fixed     bool `json:"fixed"`      // 为 true 时不根据观测延迟调整
+}
+
+// HealthConfig /readyz 各依赖的过期阈值（秒），启动后在阈值内视为就绪
+type HealthConfig struct {
+	AxiomStaleSec      int `json:"axiom_stale_sec"`      // 默认 300
+	GeckoStaleSec      int `json:"gecko_stale_sec"`      // 默认 300
+	BanListStaleSec    int `json:"ban_list_stale_sec"`   // 默认 300
+	TelegramStaleSec   int `json:"telegram_stale_sec"`   // 默认 86400，仅在有消息时才会发送
+	ScanStaleSec       int `json:"scan_stale_sec"`       // 默认 300，最近一次完成的扫描
+	DiscoveryFailAlert int `json:"discovery_fail_alert"` // 连续多少轮榜单获取失败后发送自检警报，默认 5
+}
+
+// PaperConfig 模拟盘配置，金额以 SOL 计价
+type PaperConfig struct {
+	Enabled          bool    `json:"enabled"`
+	StartingBalance  float64 `json:"starting_balance"`   // 默认 100
+	PositionSize     float64 `json:"position_size"`      // 默认 1
+	MaxPositions     int     `json:"max_positions"`      // 默认 10
+	FeePct           float64 `json:"fee_pct"`            // 单边手续费（%），默认 1，可设为 0
+	MinSlippagePct   float64 `json:"min_slippage_pct"`   // 默认 0.5，流动性冲击更大时取冲击；可设为 0
+	TakeProfitPct    float64 `json:"take_profit_pct"`    // 默认 30，0 表示不启用
+	StopLossPct      float64 `json:"stop_loss_pct"`      // 默认 15，0 表示不启用
+	TrailingStopPct  float64 `json:"trailing_stop_pct"`  // 默认 10，0 表示不启用
+	DisableMA60Exit  bool    `json:"disable_ma60_exit"`  // 不因 5m 收盘跌破 MA60 平仓
+	MaxHoldMin       int     `json:"max_hold_min"`       // 超过后平仓，0 表示不启用；空单没有 XSELL 离场时可依赖它
+	CheckIntervalSec int     `json:"check_interval_sec"` // 默认 60
+	LedgerFile       string  `json:"ledger_file"`        // 默认 DataDir/paper_ledger.json
+}
+
+// ExitConfig 离场信号配置：对近期触发过或模拟盘持有的代币每轮评估 XSELL
+type ExitConfig struct {
+	Disabled    bool `json:"disabled"`
+	WindowMin   int  `json:"window_min"`   // 信号触发后持续评估的时长，默认 240
+	CooldownMin int  `json:"cooldown_min"` // 同一代币两次 XSELL 的最小间隔，默认 30
+}
+
+// PerpConfig 币安 USDT 本位永续合约交叉上线检查
+type PerpConfig struct {
+	Enabled      bool   `json:"enabled"`
+	OverrideFile string `json:"override_file"` // 代币地址 -> 合约的手动映射，默认 DataDir/perp_overrides.json
+	RefreshMin   int    `json:"refresh_min"`   // 合约列表刷新间隔，默认 60
+}
+
+// ExecConfig 自动下单配置，需显式 enabled 才会启用
+type ExecConfig struct {
+	Enabled      bool          `json:"enabled"`
+	Venue        string        `json:"venue"`         // dry_run（默认，只记录日志）/ binance（需启用 perp）/ jupiter
+	PositionSize float64       `json:"position_size"` // 每笔名义金额：binance 为 USDT，其余为 SOL，默认 10
+	MaxPositions int           `json:"max_positions"` // 默认 3
+	CooldownMin  int           `json:"cooldown_min"`  // 同一代币两次开仓的最小间隔，默认 60
+	MaxHoldMin   int           `json:"max_hold_min"`  // 超过后自动平仓，默认 240
+	KillSwitch   bool          `json:"kill_switch"`   // 启动时即停止开仓，可通过管理接口切换
+	Binance      BinanceConfig `json:"binance"`
+	Jupiter      JupiterConfig `json:"jupiter"`
+}
+
+// BinanceConfig 币安 USDT 本位合约账户
+type BinanceConfig struct {
+	APIKey    string `json:"api_key"`
+	SecretKey string `json:"secret_key"`
+	BaseURL   string `json:"base_url"` // 为空时使用官方地址，可指向测试网
+}
+
+// JupiterConfig Jupiter 风格的 quote / swap 接口
+type JupiterConfig struct {
+	BaseURL       string `json:"base_url"` // 默认 https://quote-api.jup.ag/v6
+	UserPublicKey string `json:"user_public_key"`
+	SlippageBps   int    `json:"slippage_bps"` // 默认 100
+}
+
+// GateConfig 5m 阶段通过后的附加过滤，均基于已收盘 K 线，零值表示不启用；空头级联方向取反
+type GateConfig struct {
+	MinATRPct       float64 `json:"min_atr_pct"`        // ATR(14) 占价格的百分比下限
+	MaxATRPct       float64 `json:"max_atr_pct"`        // 上限，过滤插针剧烈的盘子
+	MaxBandWidthPct float64 `json:"max_band_width_pct"` // 布林带(20,2) 带宽上限（%）
+	VWAP            bool    `json:"vwap"`               // 收盘价需在 VWAP 之上
+	RollingVWAPBars int     `json:"rolling_vwap_bars"`  // 大于 0 时改用滚动 VWAP，否则按 UTC 日累计
+	MinADX          float64 `json:"min_adx"`            // ADX(14) 下限，且 +DI 大于 -DI
+	Supertrend      bool    `json:"supertrend"`         // Supertrend(10,3) 为多头
+	OBVBars         int     `json:"obv_bars"`           // 大于 0 时要求 OBV 在最近若干根内上升
+}
+
+// FlowConfig 成交量确认阶段，位于 gates 之后；MinSpike 与 MinBuyRatio 均为 0 时不启用
+type FlowConfig struct {
+	MinSpike     float64 `json:"min_spike"`     // 5m 已收盘 K 线成交量 ≥ 基线均量的倍数，如 2
+	BaselineBars int     `json:"baseline_bars"` // 基线均量的根数，默认 20
+	MinBuyRatio  float64 `json:"min_buy_ratio"` // 买单笔数占比下限（0~1）；空头级联要求卖单占比达到该值，无买卖数据时跳过
+}
+
+// HolderConfig 持币分布与聪明钱补充，启用后每轮扫描在分析前填充 TokenItem；规则阈值为 0 表示不启用，多空级联共用。
+// 持币增长与增长窗口内最早的一次记录比较；窗口内还没有更早的记录（首次出现或中断后重新出现）时增长未知，
+// 此时跳过增长条件、不因此拦截，其余条件照常判断
+type HolderConfig struct {
+	Enabled         bool     `json:"enabled"`
+	Providers       []string `json:"providers"`         // fixture / solana_rpc / axiom，靠前的优先，默认 fixture、axiom
+	FixtureFile     string   `json:"fixture_file"`      // 默认 DataDir/holders_fixture.json
+	RPCURL          string   `json:"rpc_url"`           // solana_rpc 使用，默认主网公共节点
+	MinHolders      int      `json:"min_holders"`       // 持币人数下限
+	MaxTop10Pct     float64  `json:"max_top10_pct"`     // 前十持币占比上限（%）
+	MinSmartDegen   int      `json:"min_smart_degen"`   // 聪明钱地址数下限
+	MinRenowned     int      `json:"min_renowned"`      // 知名地址数下限
+	MinGrowthPct    float64  `json:"min_growth_pct"`    // 持币人数在增长窗口内的增长下限（%）
+	GrowthWindowMin int      `json:"growth_window_min"` // 增长窗口（分钟），默认 60
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Level      string `json:"level"`       // debug / info / warn / error，默认 info
+	Format     string `json:"format"`      // text / json，默认 text
+	File       string `json:"file"`        // 默认 DataDir/logs/scanner.log
+	MaxSizeMB  int    `json:"max_size_mb"` // 默认 20
+	MaxBackups int    `json:"max_backups"` // 默认 5
+}
+
+// HTTPConfig HTTP 服务配置
+type HTTPConfig struct {
+	Listen               string `json:"listen"` // 默认 ":8889"
+	ReadHeaderTimeoutSec int    `json:"read_header_timeout_sec"`
+	ReadTimeoutSec       int    `json:"read_timeout_sec"`
+	WriteTimeoutSec      int    `json:"write_timeout_sec"` // SSE 连接不受此限制
+	IdleTimeoutSec       int    `json:"idle_timeout_sec"`
 }
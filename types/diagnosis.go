@@ -0,0 +1,34 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// StageDiag 单个周期阶段的指标快照
+type StageDiag struct {
+	Stage  string             `json:"stage"` // holders / 4h / 1h / 15m / 5m / gates / flow / 1m
+	Passed bool               `json:"passed"`
+	Values map[string]float64 `json:"values"`
+	Cached bool               `json:"cached,omitempty"` // 沿用上次收盘时的结果
+}
+
+// MarshalJSON 数据不足时指标为 NaN，JSON 无法表示，输出时省略
+func (d StageDiag) MarshalJSON() ([]byte, error) {
+	type plain StageDiag
+	finite := make(map[string]float64, len(d.Values))
+	for k, v := range d.Values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			finite[k] = v
+		}
+	}
+	d.Values = finite
+	return json.Marshal(plain(d))
+}
+
+// Diagnosis 最近一次分析时各阶段的指标值，分析在首个未通过的阶段结束
+type Diagnosis struct {
+	Time   time.Time   `json:"time"`
+	Stages []StageDiag `json:"stages"`
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestStageDiagJSONDropsNaN(t *testing.T) {
+	d := Diagnosis{Stages: []StageDiag{{
+		Stage:  "gates",
+		Values: map[string]float64{"atr_pct": math.NaN(), "adx": 25, "vwap": math.Inf(1)},
+	}}}
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("含 NaN 的诊断应能编码: %v", err)
+	}
+	var got Diagnosis
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	v := got.Stages[0].Values
+	if len(v) != 1 || v["adx"] != 25 {
+		t.Errorf("values = %v, want 只保留 adx", v)
+	}
+	if _, ok := d.Stages[0].Values["atr_pct"]; !ok {
+		t.Error("编码不应修改原 map")
+	}
+}
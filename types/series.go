@@ -0,0 +1,104 @@
+package types
+
+import (
+	"onchain-energe-SRSI/geckoterminal"
+	"time"
+)
+
+// GeckoTerminal K 线周期单位
+const (
+	TimeframeMinute = "minute"
+	TimeframeHour   = "hour"
+	TimeframeDay    = "day"
+)
+
+// TimeframeUnit 周期单位的时长，未知单位时为 0
+func TimeframeUnit(timeframe string) time.Duration {
+	switch timeframe {
+	case TimeframeMinute:
+		return time.Minute
+	case TimeframeHour:
+		return time.Hour
+	case TimeframeDay:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// Series 一段 K 线（时间升序，最后一根为最新），按列保存
+type Series struct {
+	Timeframe  string  // minute / hour / day
+	Aggregate  int     // 每根包含的 Timeframe 个数
+	Timestamps []int64 // 开盘时间（unix 秒）
+	Open       []float64
+	High       []float64
+	Low        []float64
+	Close      []float64
+	Volume     []float64
+	Forming    bool // 最后一根尚未收盘
+}
+
+// 柱偏移：0 为正在形成的 K 线，1 为最近收盘的 K 线，依次往前
+const (
+	BarForming    = 0
+	BarLastClosed = 1
+)
+
+// Index 柱偏移对应的下标；没有正在形成的 K 线时偏移 0 不存在
+func (s Series) Index(offset int) (int, bool) {
+	i := s.Len() - 1 - offset
+	if !s.Forming {
+		i++
+	}
+	return i, offset >= 0 && i >= 0 && i < s.Len()
+}
+
+// Len K 线根数
+func (s Series) Len() int { return len(s.Close) }
+
+// Period 每根 K 线的时长，未知周期时为 0
+func (s Series) Period() time.Duration {
+	return TimeframeUnit(s.Timeframe) * time.Duration(max(s.Aggregate, 1))
+}
+
+// Closed 去掉正在形成的最后一根
+func (s Series) Closed() Series {
+	if !s.Forming || s.Len() == 0 {
+		return s
+	}
+	n := s.Len() - 1
+	return Series{
+		Timeframe:  s.Timeframe,
+		Aggregate:  s.Aggregate,
+		Timestamps: cut(s.Timestamps, n),
+		Open:       cut(s.Open, n),
+		High:       cut(s.High, n),
+		Low:        cut(s.Low, n),
+		Close:      s.Close[:n],
+		Volume:     cut(s.Volume, n),
+	}
+}
+
+// cut 截到前 n 个；只有收盘价的序列其余列为空，原样返回
+func cut[T any](col []T, n int) []T {
+	if len(col) <= n {
+		return col
+	}
+	return col[:n]
+}
+
+// Candles 转回逐根的 OHLCV，供指标与绘图使用
+func (s Series) Candles() []geckoterminal.OHLCV {
+	res := make([]geckoterminal.OHLCV, s.Len())
+	for i := range res {
+		res[i] = geckoterminal.OHLCV{
+			Timestamp: s.Timestamps[i],
+			Open:      s.Open[i],
+			High:      s.High[i],
+			Low:       s.Low[i],
+			Close:     s.Close[i],
+			Volume:    s.Volume[i],
+		}
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package types
+
+import "testing"
+
+func TestSeriesIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		forming bool
+		n       int
+		offset  int
+		want    int
+		wantOK  bool
+	}{
+		{"有形成中：BarForming 为最后一根", true, 5, BarForming, 4, true},
+		{"有形成中：BarLastClosed 为倒数第二根", true, 5, BarLastClosed, 3, true},
+		{"无形成中：BarForming 不存在", false, 5, BarForming, 5, false},
+		{"无形成中：BarLastClosed 为最后一根", false, 5, BarLastClosed, 4, true},
+		{"无形成中：往前数到第一根", false, 5, 5, 0, true},
+		{"超出历史", false, 5, 6, -1, false},
+		{"有形成中：超出历史", true, 5, 5, -1, false},
+		{"负偏移", true, 5, -1, 5, false},
+		{"只有一根形成中：没有已收盘", true, 1, BarLastClosed, -1, false},
+		{"空序列", false, 0, BarLastClosed, -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Series{Close: make([]float64, tt.n), Forming: tt.forming}
+			i, ok := s.Index(tt.offset)
+			if ok != tt.wantOK || (ok && i != tt.want) {
+				t.Errorf("Index(%d) = %d, %v, want %d, %v", tt.offset, i, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSeriesClosed(t *testing.T) {
+	full := Series{
+		Timeframe:  TimeframeMinute,
+		Aggregate:  5,
+		Timestamps: []int64{0, 300, 600},
+		Open:       []float64{1, 2, 3},
+		High:       []float64{1.5, 2.5, 3.5},
+		Low:        []float64{0.5, 1.5, 2.5},
+		Close:      []float64{1.2, 2.2, 3.2},
+		Volume:     []float64{10, 20, 30},
+		Forming:    true,
+	}
+	c := full.Closed()
+	if c.Forming || c.Timeframe != TimeframeMinute || c.Aggregate != 5 {
+		t.Errorf("Closed() = %+v", c)
+	}
+	cols := map[string]int{
+		"Timestamps": len(c.Timestamps),
+		"Open":       len(c.Open),
+		"High":       len(c.High),
+		"Low":        len(c.Low),
+		"Close":      len(c.Close),
+		"Volume":     len(c.Volume),
+	}
+	for name, n := range cols {
+		if n != 2 {
+			t.Errorf("%s 长度 = %d, want 2", name, n)
+		}
+	}
+	if c.Timestamps[1] != 300 || c.Close[1] != 2.2 || c.Volume[1] != 20 {
+		t.Errorf("截取内容错误: %+v", c)
+	}
+	if i, ok := c.Index(BarLastClosed); !ok || c.Close[i] != 2.2 {
+		t.Errorf("Closed 后 BarLastClosed 应为原最后一根已收盘")
+	}
+
+	// 已全部收盘的序列原样返回
+	if c2 := c.Closed(); c2.Len() != 2 {
+		t.Errorf("重复 Closed() 长度 = %d", c2.Len())
+	}
+	// 只有收盘价的序列
+	if c3 := (Series{Close: []float64{1, 2}, Forming: true}).Closed(); c3.Len() != 1 || c3.Timestamps != nil {
+		t.Errorf("只有收盘价时 Closed() = %+v", c3)
+	}
+}
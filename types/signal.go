@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// 策略名称
+const (
+	StrategyCascade      = "cascade"       // 4h→1h→15m→5m→1m 多头级联
+	StrategyCascadeShort = "cascade_short" // 4h→1h→15m→5m→1m 空头级联，适用于有永续合约的代币
+)
+
+// 信号标签
+const (
+	TagBuy  = "XBUY"  // 级联买入
+	TagSell = "XSELL" // 空头级联做空，或已触发代币的离场跟进（带 ReplyTo）
+)
+
+// Signal 策略触发的信号，由 AnaylySymbol / AnaylySymbolShort / AnaylyExit 写入 resultsChan
+type Signal struct {
+	ID          string    `json:"id"` // 由 main 分发前分配
+	Strategy    string    `json:"strategy"`
+	Tag         string    `json:"tag"`                // TagBuy / TagSell
+	ReplyTo     string    `json:"reply_to,omitempty"` // 离场信号引用的原信号 ID
+	Short       bool      `json:"short,omitempty"`    // 做空方向，结果跟踪按价格下跌计收益
+	Chain       string    `json:"chain"`
+	Symbol      string    `json:"symbol"`
+	Address     string    `json:"address"`
+	PoolAddress string    `json:"pool_address"`
+	Price       float64   `json:"price"`     // 触发时使用的收盘价
+	Liquidity   float64   `json:"liquidity"` // 触发时的池子流动性（SOL）
+	Time        time.Time `json:"time"`
+	Text        string    `json:"text"` // Markdown 告警文本
+	Chart       []byte    `json:"-"`    // 可选的 PNG 图表
+
+	Perp *PerpListing `json:"perp,omitempty"` // 已上线中心化交易所永续合约时填写
+}
+
+// PerpListing 代币对应的永续合约行情
+type PerpListing struct {
+	Exchange        string  `json:"exchange"` // 目前仅 binance
+	Symbol          string  `json:"symbol"`
+	MarkPrice       float64 `json:"mark_price"`
+	FundingRate     float64 `json:"funding_rate"`      // 最近一次资金费率，0.0001 即 0.01%
+	OpenInterest    float64 `json:"open_interest"`     // 以标的计
+	OpenInterestUSD float64 `json:"open_interest_usd"` // 按标记价格折算
+}
@@ -12,5 +12,26 @@ type TokenData struct {
 	TokenItem   TokenItem
 	Data        []geckoterminal.OHLCV // 保存最新数据
 	LastUpdated time.Time
+	Diag        Diagnosis             // 最近一次分析的各阶段指标
+	ShortDiag   Diagnosis             // 最近一次空头级联分析的各阶段指标，未启用时为空
+	Stages      map[string]StageCache // 高周期阶段结果，下一根 K 线收盘前复用
+	Holders     HolderGrowth          // 增长窗口内的持币人数变化
 	Mutex       sync.Mutex
 }
+
+// HolderGrowth 持币人数相对增长窗口内最早一次记录的变化，Known 为 false 时表示窗口内没有更早的记录或人数未知
+type HolderGrowth struct {
+	Known    bool      `json:"known"`
+	Previous int       `json:"previous"`
+	Delta    int       `json:"delta"`
+	Pct      float64   `json:"pct"`
+	Since    time.Time `json:"since"` // 比较基准的记录时间
+}
+
+// StageCache 某周期阶段在一次收盘后的结果
+type StageCache struct {
+	Pool   string    // 池地址变化时失效
+	Close  time.Time // 结果对应的收盘时间
+	Diag   StageDiag
+	Series Series // 阶段使用的 K 线，绘图时复用
+}
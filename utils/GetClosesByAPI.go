@@ -1,38 +1,123 @@
 package utils
 
 import (
+	"context"
 	"fmt"
 	"onchain-energe-SRSI/geckoterminal"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
 	"onchain-energe-SRSI/types"
+	"strconv"
 	"time"
 )
 
-func GetClosesByAPI(tokenItem types.TokenItem, config *types.Config, options map[string]string, TF string) (closes []float64, err error) {
-	var ohlcvData []geckoterminal.OHLCV
+// GetClosesByAPI 只取收盘价，GetSeries 的简化版本
+func GetClosesByAPI(ctx context.Context, tokenItem types.TokenItem, config *types.Config, options map[string]string, TF string) (closes []float64, err error) {
+	series, err := GetSeries(ctx, tokenItem, config, options, TF)
+	if err != nil {
+		return nil, err
+	}
+	return series.Close, nil
+}
+
+// GetSeries 获取 K 线并按列返回，标记最后一根是否仍在形成
+func GetSeries(ctx context.Context, tokenItem types.TokenItem, config *types.Config, options map[string]string, TF string) (types.Series, error) {
+	ohlcvData, err := GetOHLCVByAPI(ctx, tokenItem, config, options, TF)
+	if err != nil {
+		return types.Series{}, err
+	}
+	aggregate, _ := strconv.Atoi(options["aggregate"])
+	return SeriesOf(ohlcvData, TF, aggregate, time.Now()), nil
+}
+
+// SeriesOf 把升序 K 线转为 Series；最后一根的收盘时间晚于 now 时视为仍在形成
+func SeriesOf(ohlcvData []geckoterminal.OHLCV, TF string, aggregate int, now time.Time) types.Series {
+	n := len(ohlcvData)
+	s := types.Series{
+		Timeframe:  TF,
+		Aggregate:  max(aggregate, 1),
+		Timestamps: make([]int64, n),
+		Open:       make([]float64, n),
+		High:       make([]float64, n),
+		Low:        make([]float64, n),
+		Close:      make([]float64, n),
+		Volume:     make([]float64, n),
+	}
+	for i, k := range ohlcvData {
+		s.Timestamps[i] = k.Timestamp
+		s.Open[i], s.High[i], s.Low[i], s.Close[i], s.Volume[i] = k.Open, k.High, k.Low, k.Close, k.Volume
+	}
+	if n > 0 && s.Period() > 0 {
+		s.Forming = time.Unix(s.Timestamps[n-1], 0).Add(s.Period()).After(now)
+	}
+	return s
+}
+
+// GetOHLCVByAPI 获取完整K线（时间升序，最后一根为最新），ctx 取消时停止重试
+func GetOHLCVByAPI(ctx context.Context, tokenItem types.TokenItem, config *types.Config, options map[string]string, TF string) (ohlcvData []geckoterminal.OHLCV, err error) {
 	// 循环尝试获取数据，直到成功或达到最大重试次数
 	maxRetries := 3
 	for i := 0; i < maxRetries; i++ {
-		ohlcvData, _, err = geckoterminal.GetOHLCV(tokenItem.Chain, tokenItem.PoolAddress, TF, options, config.Proxy)
+		if i > 0 {
+			metrics.Retries.Inc(metrics.ServiceGecko)
+		}
+		ohlcvData, _, err = geckoterminal.GetOHLCV(ctx, tokenItem.Chain, tokenItem.PoolAddress, TF, options, config.Proxy)
 		if err == nil {
 			// 获取成功，退出循环
 			break
 		}
-		time.Sleep(2 * time.Second) // 等待2秒后重试
+		if SleepContext(ctx, 2*time.Second) != nil { // 等待2秒后重试
+			return nil, fmt.Errorf("[%s] 获取OHLCV数据中断: %w", tokenItem.Symbol, err)
+		}
 	}
 
 	// 如果最终仍然失败
 	if err != nil || len(ohlcvData) == 0 {
-		fmt.Printf("[%s] 多次尝试后获取OHLCV数据失败: %v\n", tokenItem.Symbol, err)
+		logging.From(ctx).Warn("多次尝试后获取K线失败", logging.KeyTimeframe, TF+"/"+options["aggregate"], "err", err)
 		return
-	} else {
-		for i, j := 0, len(ohlcvData)-1; i < j; i, j = i+1, j-1 {
-			ohlcvData[i], ohlcvData[j] = ohlcvData[j], ohlcvData[i]
-		}
 	}
+	for i, j := 0, len(ohlcvData)-1; i < j; i, j = i+1, j-1 {
+		ohlcvData[i], ohlcvData[j] = ohlcvData[j], ohlcvData[i]
+	}
+
+	return ohlcvData, nil
+}
+
+// ClosesOf 提取收盘价序列
+func ClosesOf(ohlcvData []geckoterminal.OHLCV) []float64 {
+	closes := make([]float64, len(ohlcvData))
+	for i, k := range ohlcvData {
+		closes[i] = k.Close
+	}
+	return closes
+}
 
-	for _, k := range ohlcvData {
-		closes = append(closes, k.Close)
+// GetLatestPrice 以最新 1 分钟 K 线收盘价作为当前价格
+func GetLatestPrice(ctx context.Context, tokenItem types.TokenItem, config *types.Config) (float64, error) {
+	options := map[string]string{
+		"aggregate": "1",
+		"limit":     "2",
+		"token":     "base",
+		"currency":  "usd",
+	}
+	closes, err := GetClosesByAPI(ctx, tokenItem, config, options, types.TimeframeMinute)
+	if err != nil {
+		return 0, err
 	}
+	if len(closes) == 0 {
+		return 0, fmt.Errorf("[%s] 无最新K线", tokenItem.Symbol)
+	}
+	return closes[len(closes)-1], nil
+}
 
-	return closes, err
+// SleepContext 等待 d，ctx 先取消时提前返回 ctx.Err()
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/types"
+	"time"
+)
+
+// AnaylyExit 买入级联的镜像，对已触发的代币检查 5m、1m 是否转弱，触发时写入引用原信号的 XSELL
+// 5m：DIF 为负；1m：DIF 在最近收盘的 K 线下穿零轴、柱线连续两根下降，且收盘价跌破 MA60 或 EMA25（均取已收盘 K 线）。
+// 只在下穿当根触发，DIF 持续为负时不会重复发出
+func AnaylyExit(ctx context.Context, origin types.Signal, config *types.Config, resultsChan chan<- types.Signal) (fired bool) {
+	logger := logging.From(ctx)
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.PanicsRecovered.Inc("exit_analysis")
+			logger.Error("离场分析时 panic，已恢复", "panic", r)
+		}
+	}()
+
+	tokenItem := types.TokenItem{
+		Chain:       origin.Chain,
+		Symbol:      origin.Symbol,
+		Address:     origin.Address,
+		PoolAddress: origin.PoolAddress,
+	}
+
+	//5分钟检查
+	optionsM5 := map[string]string{
+		"aggregate":               config.FiveAggregate,
+		"limit":                   "200",
+		"token":                   "base",
+		"currency":                "usd",
+		"include_empty_intervals": "true",
+	}
+	seriesM5, err := GetSeries(ctx, tokenItem, config, optionsM5, config.Timeframe)
+	if err != nil {
+		logger.Warn("获取K线失败", logging.KeyStage, "exit_5m", logging.KeyTimeframe, config.Timeframe+"/"+config.FiveAggregate, "err", err)
+		return false
+	}
+	bar := types.BarLastClosed
+	frameM5 := NewFrame(seriesM5)
+	if frameM5.bars(bar) < 2 {
+		return false
+	}
+	difM5 := frameM5.DIF(bar)
+	logger.Debug("离场阶段结果", logging.KeyStage, "exit_5m", "passed", difM5 < 0, "dif", difM5)
+	if difM5 >= 0 {
+		return false
+	}
+
+	//1分钟检查
+	optionsM1 := map[string]string{
+		"aggregate":               config.OneAggregate,
+		"limit":                   "200",
+		"token":                   "base",
+		"currency":                "usd",
+		"include_empty_intervals": "true",
+	}
+	seriesM1, err := GetSeries(ctx, tokenItem, config, optionsM1, config.Timeframe)
+	if err != nil {
+		logger.Warn("获取K线失败", logging.KeyStage, "exit_1m", logging.KeyTimeframe, config.Timeframe+"/"+config.OneAggregate, "err", err)
+		return false
+	}
+	frameM1 := NewFrame(seriesM1)
+	if frameM1.bars(bar) < max(frameM1.MACD.FastPeriod(), 3) {
+		return false
+	}
+	priceM1 := frameM1.Price(bar)
+	ma60M1 := frameM1.MA(60, bar)
+	ema25M1 := frameM1.EMA(25, bar)
+	difM1 := frameM1.DIF(bar)
+	crossed := frameM1.CrossDown(bar)
+	// 最近两根已收盘 K 线柱值依次下降
+	histFalling := frameM1.HistFalling(bar, 2)
+	lostMA := priceM1 < ma60M1 || priceM1 < ema25M1
+
+	passed := crossed && histFalling && lostMA
+	logger.Debug("离场阶段结果", logging.KeyStage, "exit_1m", "passed", passed,
+		"dif", difM1, "hist", frameM1.Hist(bar), "price", priceM1, "ma60", ma60M1, "ema25", ema25M1)
+	if !passed {
+		return false
+	}
+
+	lost := "MA60"
+	switch {
+	case priceM1 < ma60M1 && priceM1 < ema25M1:
+		lost = "MA60/EMA25"
+	case priceM1 < ema25M1:
+		lost = "EMA25"
+	}
+	msg := fmt.Sprintf("🔻%s %s\n📬 `%s`\n5m DIF 为负，1m DIF 下穿零轴、柱线连降两根，跌破 %s", types.TagSell, tokenItem.Symbol, tokenItem.Address, lost)
+	if origin.Price > 0 {
+		msg += fmt.Sprintf("\n信号价 %.6g → %.6g（%+.1f%%）", origin.Price, priceM1, (priceM1/origin.Price-1)*100)
+	}
+	resultsChan <- types.Signal{
+		Strategy:    origin.Strategy,
+		Tag:         types.TagSell,
+		ReplyTo:     origin.ID,
+		Chain:       origin.Chain,
+		Symbol:      origin.Symbol,
+		Address:     origin.Address,
+		PoolAddress: origin.PoolAddress,
+		Price:       priceM1,
+		Time:        time.Now(),
+		Text:        msg,
+	}
+	return true
+}
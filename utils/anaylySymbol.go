@@ -1,25 +1,126 @@
 package utils
 
 import (
+	"context"
 	"fmt"
-	"onchain-energe-SRSI/telegram"
+	"onchain-energe-SRSI/flow"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
+	"onchain-energe-SRSI/schedule"
 	"onchain-energe-SRSI/types"
+	"strconv"
+	"time"
 )
 
-// AnaylySymbol  一次性检查1h, 15m,5m,1m
-func AnaylySymbol(data *types.TokenData, config *types.Config, resultsChan chan types.TokenItem, wait_sucess_token, chatID string) {
+// AnaylySymbol  一次性检查4h, 1h, 15m,5m,1m，返回是否触发买入信号；ctx 取消或超时时放弃本次分析
+func AnaylySymbol(ctx context.Context, data *types.TokenData, config *types.Config, resultsChan chan<- types.Signal) (fired bool) {
+	return analyse(ctx, longCascade, data, config, resultsChan)
+}
+
+// cascade 级联方向：多头与空头走同一套流程，只替换比较方向、标签与结果的记录位置
+type cascade struct {
+	strategy string
+	valid    string // 4h~5m 阶段通过时的标记：BUYMACD / BEARMACD
+	tag      string // 1m 通过时的信号标签
+	short    bool
+	prefix   string // 阶段缓存与指标标签的前缀，避免多空结果互相覆盖
+}
+
+var longCascade = cascade{strategy: types.StrategyCascade, valid: "BUYMACD", tag: types.TagBuy}
+
+// beyond 多头要求 a 在 b 之上，空头要求在其之下
+func (c cascade) beyond(a, b float64) bool {
+	if c.short {
+		return a < b
+	}
+	return a > b
+}
+
+// dif 多头 DIF 为正，空头为负
+func (c cascade) dif(f *Frame, bar int) bool {
+	if c.short {
+		return f.DIFDown(bar)
+	}
+	return f.DIFUp(bar)
+}
+
+// momentum 柱线与 DIF 同升（空头同降）
+func (c cascade) momentum(f *Frame, bar int) bool {
+	if c.short {
+		return f.Falling(bar)
+	}
+	return f.Rising(bar)
+}
+
+func (c cascade) frame(tf schedule.Timeframe) schedule.Timeframe {
+	tf.Name = c.prefix + tf.Name
+	return tf
+}
+
+// headline 告警首行
+func (c cascade) headline(item types.TokenItem) string {
+	if c.short {
+		return fmt.Sprintf("🐻%s %s%s\n📬 `%s`", c.tag, item.Emoje, item.Symbol, item.Address)
+	}
+	return fmt.Sprintf("%s%s\n📬 `%s`", item.Emoje, item.Symbol, item.Address)
+}
+
+// analyse 按方向执行级联：holders / 4h / 1h / 15m / 5m / gates / flow / 1m，在首个未通过的阶段结束
+func analyse(ctx context.Context, c cascade, data *types.TokenData, config *types.Config, resultsChan chan<- types.Signal) (fired bool) {
 	data.Mutex.Lock()
 	defer data.Mutex.Unlock()
+	logger := logging.From(ctx).With("strategy", c.strategy)
+
+	// 记录各阶段指标，供 /diag 查看
+	diag := types.Diagnosis{Time: time.Now()}
+	stage := func(name string, passed bool, values map[string]float64) {
+		diag.Stages = append(diag.Stages, types.StageDiag{Stage: name, Passed: passed, Values: values})
+	}
+	defer func() {
+		if c.short {
+			data.ShortDiag = diag
+		} else {
+			data.Diag = diag
+			data.LastUpdated = diag.Time
+		}
+		for _, st := range diag.Stages {
+			result := "fail"
+			if st.Passed {
+				result = "pass"
+			}
+			metrics.StageEvaluations.Inc(c.prefix+st.Stage, result)
+			logger.Debug("阶段结果", logging.KeyStage, st.Stage, "passed", st.Passed, "cached", st.Cached)
+		}
+	}()
 	defer func() {
 		if r := recover(); r != nil {
-			progressLogger.Printf("[analyseSymbolForSignal] panic recovered %s : %v\n", data.TokenItem.Symbol, r)
+			label := "analysis"
+			if c.short {
+				label = "analysis_short"
+			}
+			metrics.PanicsRecovered.Inc(label)
+			logger.Error("分析时 panic，已恢复", "panic", r)
 		}
 	}()
 
 	tokenItem := data.TokenItem
-	validMACD := "BUYMACD"
+	validMACD := c.valid
+	// 各周期的价格、均线与 MACD 都取同一根已收盘 K 线
+	bar := types.BarLastClosed
 
-	//4小时检查
+	// 持币与聪明钱过滤，数据由 main 在分析前填充
+	holderLine := ""
+	if st, ok := HolderStage(tokenItem, data.Holders, config.Holders); ok {
+		diag.Stages = append(diag.Stages, st)
+		if !st.Passed {
+			return false
+		}
+	}
+	if config.Holders.Enabled && tokenItem.HolderCount > 0 {
+		holderLine = "\n" + FormatHolders(tokenItem, data.Holders)
+	}
+
+	//4小时检查（4h/1h/15m 只在各自收盘后重新计算）
 	optionsH4 := map[string]string{
 		"aggregate":               "4",
 		"limit":                   "200",
@@ -27,21 +128,29 @@ func AnaylySymbol(data *types.TokenData, config *types.Config, resultsChan chan
 		"currency":                "usd",
 		"include_empty_intervals": "true",
 	}
+	stH4, _, err := cachedStage(data, c.frame(schedule.H4), diag.Time, func() (types.StageDiag, types.Series, error) {
+		seriesH4, err := GetSeries(ctx, tokenItem, config, optionsH4, types.TimeframeHour)
+		if err != nil {
+			return types.StageDiag{}, types.Series{}, err
+		}
+		frameH4 := NewFrame(seriesH4)
+		price := frameH4.Price(bar)
+		EMA25H4NOW := frameH4.EMA(25, bar)
 
-	closesH4, err := GetClosesByAPI(tokenItem, config, optionsH4, "hour")
+		MACDH4 := "RANGE"
+		if c.beyond(price, EMA25H4NOW) && c.momentum(frameH4, bar) {
+			MACDH4 = validMACD
+		}
+		return types.StageDiag{Stage: "4h", Passed: MACDH4 == validMACD,
+			Values: map[string]float64{"price": price, "ema25": EMA25H4NOW, "dif": frameH4.DIF(bar), "hist": frameH4.Hist(bar)}}, seriesH4, nil
+	})
 	if err != nil {
-		fmt.Println(err)
+		logger.Warn("获取K线失败", logging.KeyStage, "4h", logging.KeyTimeframe, "hour/4", "err", err)
+		return false
 	}
-	price := closesH4[len(closesH4)-2]
-	_, EMA25H4NOW := CalculateEMA(closesH4, 25)
-	ColANDDIFUPH4 := ColANDDIFUP(closesH4, 6, 13, 5)
-
-	MACDH4 := "RANGE"
-	if price > EMA25H4NOW && ColANDDIFUPH4 {
-		MACDH4 = "BUYMACD"
-	}
-	if MACDH4 != validMACD {
-		return
+	diag.Stages = append(diag.Stages, stH4)
+	if !stH4.Passed {
+		return false
 	}
 
 	//1小时检查
@@ -52,17 +161,26 @@ func AnaylySymbol(data *types.TokenData, config *types.Config, resultsChan chan
 		"currency":                "usd",
 		"include_empty_intervals": "true",
 	}
-	closesH1, err := GetClosesByAPI(tokenItem, config, optionsH1, "hour")
+	stH1, seriesH1, err := cachedStage(data, c.frame(schedule.H1), diag.Time, func() (types.StageDiag, types.Series, error) {
+		seriesH1, err := GetSeries(ctx, tokenItem, config, optionsH1, types.TimeframeHour)
+		if err != nil {
+			return types.StageDiag{}, types.Series{}, err
+		}
+		frameH1 := NewFrame(seriesH1)
+		MACDH1 := "RANGE"
+		if c.dif(frameH1, bar) { //1H :DIF
+			MACDH1 = validMACD
+		}
+		return types.StageDiag{Stage: "1h", Passed: MACDH1 == validMACD,
+			Values: frameH1.MACDValues(bar)}, seriesH1, nil
+	})
 	if err != nil {
-		fmt.Println(err)
+		logger.Warn("获取K线失败", logging.KeyStage, "1h", logging.KeyTimeframe, "hour/1", "err", err)
+		return false
 	}
-	DIFH1 := IsDIFUP(closesH1, 6, 13, 5)
-	MACDH1 := "RANGE"
-	if DIFH1 { //1H :DIF
-		MACDH1 = "BUYMACD"
-	}
-	if MACDH1 != validMACD {
-		return
+	diag.Stages = append(diag.Stages, stH1)
+	if !stH1.Passed {
+		return false
 	}
 
 	//15分钟检查
@@ -73,22 +191,29 @@ func AnaylySymbol(data *types.TokenData, config *types.Config, resultsChan chan
 		"currency":                "usd",
 		"include_empty_intervals": "true",
 	}
+	stM15, _, err := cachedStage(data, c.frame(aggregateFrame("15m", config.Timeframe, config.FifteenAggregate)), diag.Time, func() (types.StageDiag, types.Series, error) {
+		seriesM15, err := GetSeries(ctx, tokenItem, config, options, config.Timeframe)
+		if err != nil {
+			return types.StageDiag{}, types.Series{}, err
+		}
+		frameM15 := NewFrame(seriesM15)
+		price := frameM15.Price(bar)
+		EMA25M15NOW := frameM15.EMA(25, bar)
 
-	closesM15, err := GetClosesByAPI(tokenItem, config, options, config.Timeframe)
+		MACDM15 := "RANGE"
+		if c.beyond(price, EMA25M15NOW) && c.dif(frameM15, bar) && c.momentum(frameM15, bar) {
+			MACDM15 = validMACD
+		}
+		return types.StageDiag{Stage: "15m", Passed: MACDM15 == validMACD,
+			Values: map[string]float64{"price": price, "ema25": EMA25M15NOW, "dif": frameM15.DIF(bar), "hist": frameM15.Hist(bar)}}, seriesM15, nil
+	})
 	if err != nil {
-		fmt.Println(err)
-	}
-	price = closesM15[len(closesM15)-2]
-	_, EMA25M15NOW := CalculateEMA(closesM15, 25)
-	ColANDDIFUPM15 := ColANDDIFUP(closesM15, 6, 13, 5)
-	DIFM15UP := IsDIFUP(closesM15, 6, 13, 5)
-
-	MACDM15 := "RANGE"
-	if price > EMA25M15NOW && DIFM15UP && ColANDDIFUPM15 {
-		MACDM15 = "BUYMACD"
+		logger.Warn("获取K线失败", logging.KeyStage, "15m", logging.KeyTimeframe, config.Timeframe+"/"+config.FifteenAggregate, "err", err)
+		return false
 	}
-	if MACDM15 != validMACD {
-		return
+	diag.Stages = append(diag.Stages, stM15)
+	if !stM15.Passed {
+		return false
 	}
 
 	//5分钟检查
@@ -99,20 +224,40 @@ func AnaylySymbol(data *types.TokenData, config *types.Config, resultsChan chan
 		"currency":                "usd",
 		"include_empty_intervals": "true",
 	}
-	closesM5, err := GetClosesByAPI(tokenItem, config, optionsM5, config.Timeframe)
+	seriesM5, err := GetSeries(ctx, tokenItem, config, optionsM5, config.Timeframe)
 	if err != nil {
-		fmt.Println(err)
+		logger.Warn("获取K线失败", logging.KeyStage, "5m", logging.KeyTimeframe, config.Timeframe+"/"+config.FiveAggregate, "err", err)
+		return false
 	}
-	priceM5 := closesM5[len(closesM5)-2]
-	ma60M5 := CalculateMA(closesM5, 60)
-	DIFUPM5 := IsDIFUP(closesM5, 6, 13, 5)
+	frameM5 := NewFrame(seriesM5)
+	priceM5 := frameM5.Price(bar)
+	ma60M5 := frameM5.MA(60, bar)
 
 	MACDM5 := "RANGE"
-	if priceM5 > ma60M5 && DIFUPM5 {
-		MACDM5 = "BUYMACD"
+	if c.beyond(priceM5, ma60M5) && c.dif(frameM5, bar) {
+		MACDM5 = validMACD
 	}
+	stage("5m", MACDM5 == validMACD, map[string]float64{"price": priceM5, "ma60": ma60M5, "dif": frameM5.DIF(bar), "hist": frameM5.Hist(bar)})
 	if MACDM5 != validMACD {
-		return
+		return false
+	}
+
+	// 波动率 / 成交量过滤
+	if st, ok := GateStage(seriesM5, config.Gates, c.short); ok {
+		diag.Stages = append(diag.Stages, st)
+		if !st.Passed {
+			return false
+		}
+	}
+
+	// 成交量确认，数值附在告警中
+	flowLine := ""
+	if st, stats, ok := FlowStage(seriesM5, tokenItem, config.Flow, c.short); ok {
+		diag.Stages = append(diag.Stages, st)
+		if !st.Passed {
+			return false
+		}
+		flowLine = "\n" + flow.Format(stats, config.Flow.BaselineBars)
 	}
 
 	//1分钟检查
@@ -123,26 +268,90 @@ func AnaylySymbol(data *types.TokenData, config *types.Config, resultsChan chan
 		"currency":                "usd",
 		"include_empty_intervals": "true",
 	}
-	closesM1, err := GetClosesByAPI(tokenItem, config, optionsM1, config.Timeframe)
+	seriesM1, err := GetSeries(ctx, tokenItem, config, optionsM1, config.Timeframe)
 	if err != nil {
-		fmt.Println(err)
+		logger.Warn("获取K线失败", logging.KeyStage, "1m", logging.KeyTimeframe, config.Timeframe+"/"+config.OneAggregate, "err", err)
+		return false
 	}
-	priceM1 := closesM1[len(closesM1)-2]
-	ma60M1 := CalculateMA(closesM1, 60)
-	DIFUPM1 := IsDIFUP(closesM1, 6, 13, 5)
-	ColANDDIFUPM1 := ColANDDIFUPMicro(closesM1, 6, 13, 5)
+	frameM1 := NewFrame(seriesM1)
+	priceM1 := frameM1.Price(bar)
+	ma60M1 := frameM1.MA(60, bar)
 
 	MACDM1 := ""
-	if priceM1 > ma60M1 && DIFUPM1 && ColANDDIFUPM1 {
-		MACDM1 = "XBUY"
+	if c.beyond(priceM1, ma60M1) && c.dif(frameM1, bar) && c.momentum(frameM1, bar) {
+		MACDM1 = c.tag
+	}
+	stage("1m", MACDM1 == c.tag, map[string]float64{"price": priceM1, "ma60": ma60M1, "dif": frameM1.DIF(bar), "hist": frameM1.Hist(bar)})
+	if MACDM1 != c.tag {
+		return false
 	}
 
-	if MACDH4 == validMACD && MACDH1 == validMACD && MACDM15 == validMACD && MACDM5 == validMACD && MACDM1 == "XBUY" {
-		msg := fmt.Sprintf("%s%s\n📬 `%s`", tokenItem.Emoje, tokenItem.Symbol, tokenItem.Address)
-		// 错误注释：Telegram 发送失败依赖其内置重试机制，失败后跳过状态更新
-		if err := telegram.SendMarkdownMessage(wait_sucess_token, chatID, msg); err != nil {
-			fmt.Printf("发送 Telegram 买入消息失败 (%s): %v\n", tokenItem.Symbol, err)
-			return
+	msg := c.headline(tokenItem) + flowLine + holderLine
+	// 附带 5m/1h 图，绘制失败时仅发送文字
+	var photo []byte
+	if !config.DisableCharts {
+		if photo, err = RenderSignalChart(tokenItem.Symbol, seriesM5.Candles(), seriesH1.Candles()); err != nil {
+			logger.Warn("绘制图表失败", "err", err)
 		}
 	}
+	// 交给 main 分发到通知渠道，避免持锁等待发送
+	resultsChan <- types.Signal{
+		Strategy:    c.strategy,
+		Tag:         MACDM1,
+		Short:       c.short,
+		Chain:       tokenItem.Chain,
+		Symbol:      tokenItem.Symbol,
+		Address:     tokenItem.Address,
+		PoolAddress: tokenItem.PoolAddress,
+		Price:       priceM1,
+		Liquidity:   tokenItem.Liquidity,
+		Time:        time.Now(),
+		Text:        msg,
+		Chart:       photo,
+	}
+	return true
+}
+
+// cachedStage 在该周期下一根 K 线收盘前复用上次结果；eval 出错或上游尚未生成刚收盘的 K 线时不缓存
+func cachedStage(data *types.TokenData, tf schedule.Timeframe, now time.Time,
+	eval func() (types.StageDiag, types.Series, error)) (types.StageDiag, types.Series, error) {
+	closeTime := tf.LastClose(now)
+	if c, ok := data.Stages[tf.Name]; ok && c.Pool == data.TokenItem.PoolAddress && c.Close.Equal(closeTime) {
+		c.Diag.Cached = true
+		return c.Diag, c.Series, nil
+	}
+
+	st, series, err := eval()
+	if err != nil {
+		return st, series, err
+	}
+	if !barPublished(series, tf, closeTime) {
+		return st, series, nil
+	}
+	if data.Stages == nil {
+		data.Stages = make(map[string]types.StageCache)
+	}
+	data.Stages[tf.Name] = types.StageCache{
+		Pool:   data.TokenItem.PoolAddress,
+		Close:  closeTime,
+		Diag:   st,
+		Series: series,
+	}
+	return st, series, nil
+}
+
+// barPublished 序列中最近收盘的 K 线是否正是在 closeTime 收盘的那根
+func barPublished(series types.Series, tf schedule.Timeframe, closeTime time.Time) bool {
+	i, ok := series.Index(types.BarLastClosed)
+	return ok && series.Timestamps[i] == closeTime.Add(-tf.Period).Unix()
+}
+
+// aggregateFrame 以周期单位与聚合数构造周期，配置无效时退回默认 15 分钟
+func aggregateFrame(name, timeframe, aggregate string) schedule.Timeframe {
+	unit := types.TimeframeUnit(timeframe)
+	n, err := strconv.Atoi(aggregate)
+	if unit == 0 || err != nil || n <= 0 {
+		return schedule.M15
+	}
+	return schedule.Timeframe{Name: name, Period: time.Duration(n) * unit}
 }
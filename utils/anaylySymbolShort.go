@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"context"
+	"onchain-energe-SRSI/types"
+)
+
+var shortCascade = cascade{
+	strategy: types.StrategyCascadeShort,
+	valid:    "BEARMACD",
+	tag:      types.TagSell,
+	short:    true,
+	prefix:   "short_",
+}
+
+// AnaylySymbolShort 空头级联，与 AnaylySymbol 对称：价格在 EMA/MA 之下、DIF 为负、柱线下降，返回是否触发做空信号
+func AnaylySymbolShort(ctx context.Context, data *types.TokenData, config *types.Config, resultsChan chan<- types.Signal) (fired bool) {
+	return analyse(ctx, shortCascade, data, config, resultsChan)
+}
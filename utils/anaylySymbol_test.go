@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/schedule"
+	"onchain-energe-SRSI/types"
+	"testing"
+	"time"
+)
+
+// hourSeries 以 lastOpen 为最后一根开盘时间的 1h 序列
+func hourSeries(lastOpen time.Time, n int, forming bool) types.Series {
+	s := types.Series{Timeframe: types.TimeframeHour, Aggregate: 1, Forming: forming}
+	for i := n - 1; i >= 0; i-- {
+		s.Timestamps = append(s.Timestamps, lastOpen.Add(-time.Duration(i)*time.Hour).Unix())
+		s.Close = append(s.Close, 1)
+	}
+	return s
+}
+
+func TestCachedStageSkipsUnpublishedBar(t *testing.T) {
+	closeTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := closeTime.Add(30 * time.Second)
+	tests := []struct {
+		name   string
+		series types.Series
+		cached bool
+	}{
+		{"刚收盘的 K 线已生成，另有正在形成的一根", hourSeries(closeTime, 10, true), true},
+		{"刚收盘的 K 线是最后一根", hourSeries(closeTime.Add(-time.Hour), 10, false), true},
+		{"上游仍停在上一根", hourSeries(closeTime.Add(-2*time.Hour), 10, false), false},
+		{"空序列", types.Series{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &types.TokenData{}
+			calls := 0
+			eval := func() (types.StageDiag, types.Series, error) {
+				calls++
+				return types.StageDiag{Stage: "1h", Passed: true}, tt.series, nil
+			}
+			cachedStage(data, schedule.H1, now, eval)
+			st, _, _ := cachedStage(data, schedule.H1, now.Add(time.Minute), eval)
+			if st.Cached != tt.cached {
+				t.Errorf("cached = %v, want %v", st.Cached, tt.cached)
+			}
+			if want := map[bool]int{true: 1, false: 2}[tt.cached]; calls != want {
+				t.Errorf("eval 调用 %d 次, want %d", calls, want)
+			}
+		})
+	}
+}
+
+// accel 加速上涨（sign=1）或加速下跌（sign=-1），DIF 与柱线持续同向扩大
+func accel(n int, sign float64) []float64 {
+	c := make([]float64, n)
+	for i := range c {
+		c[i] = 100 + sign*float64(i*i)*0.01
+	}
+	return c
+}
+
+func TestCascadeDirection(t *testing.T) {
+	up := closed(accel(40, 1))
+	down := closed(accel(40, -1))
+	tests := []struct {
+		name   string
+		c      cascade
+		f      *Frame
+		beyond bool // 价格 2 对比均线 1
+		pass   bool // DIF 与动能
+	}{
+		{"多头遇上涨", longCascade, up, true, true},
+		{"多头遇下跌", longCascade, down, true, false},
+		{"空头遇下跌", shortCascade, down, false, true},
+		{"空头遇上涨", shortCascade, up, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.beyond(2, 1); got != tt.beyond {
+				t.Errorf("beyond = %v, want %v", got, tt.beyond)
+			}
+			bar := types.BarLastClosed
+			if got := tt.c.dif(tt.f, bar) && tt.c.momentum(tt.f, bar); got != tt.pass {
+				t.Errorf("dif && momentum = %v, want %v", got, tt.pass)
+			}
+		})
+	}
+	if longCascade.frame(schedule.H4).Name == shortCascade.frame(schedule.H4).Name {
+		t.Error("多空阶段缓存不应共用同一键")
+	}
+}
+
+func TestAggregateFrame(t *testing.T) {
+	tests := []struct {
+		name                 string
+		timeframe, aggregate string
+		want                 time.Duration
+	}{
+		{"分钟", types.TimeframeMinute, "15", 15 * time.Minute},
+		{"小时", types.TimeframeHour, "1", time.Hour},
+		{"天", types.TimeframeDay, "1", 24 * time.Hour},
+		{"未知单位退回 15 分钟", "week", "1", 15 * time.Minute},
+		{"聚合数无效退回 15 分钟", types.TimeframeMinute, "x", 15 * time.Minute},
+		{"聚合数为 0 退回 15 分钟", types.TimeframeMinute, "0", 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aggregateFrame("15m", tt.timeframe, tt.aggregate); got.Period != tt.want {
+				t.Errorf("Period = %v, want %v", got.Period, tt.want)
+			}
+		})
+	}
+}
@@ -26,3 +26,21 @@ func CalculateMA(data []float64, period int) float64 {
 	}
 	return sum / float64(period)
 }
+
+// CalculateMASeries 返回与 data 等长的简单均线序列，前 period-1 个值为 NaN
+func CalculateMASeries(data []float64, period int) []float64 {
+	res := make([]float64, len(data))
+	sum := 0.0
+	for i, v := range data {
+		sum += v
+		if i >= period {
+			sum -= data[i-period]
+		}
+		if i < period-1 {
+			res[i] = math.NaN()
+			continue
+		}
+		res[i] = sum / float64(period)
+	}
+	return res
+}
@@ -1,11 +1,6 @@
 package utils
 
-import (
-	"log"
-	"os"
-)
-
-var progressLogger = log.New(os.Stdout, "[Screener] ", log.LstdFlags)
+import "onchain-energe-SRSI/indicator"
 
 // 计算 MACD：12EMA快线，26EMA慢线，9MACD信号，返回MACD集合，信号集合，柱子集合
 func CalculateMACD(closePrices []float64, fastPeriod, slowPeriod, signalPeriod int) (macdLine, signalLine, histogram []float64) {
@@ -23,62 +18,7 @@ func CalculateMACD(closePrices []float64, fastPeriod, slowPeriod, signalPeriod i
 	return
 }
 
-// DIF正 比较DIF与0值（100%正确）
-func IsDIFUP(closePrices []float64, fastPeriod, slowPeriod, signalPeriod int) bool {
-	if len(closePrices) < fastPeriod {
-		return true
-	}
-	DIF, _, _ := CalculateMACD(closePrices, fastPeriod, slowPeriod, signalPeriod)
-	if len(DIF) < 1 {
-		return true
-	}
-	return DIF[len(DIF)-1] > 0
-}
-
-// 当下柱线同升	（100%正确）
-func ColANDDIFUP(closePrices []float64, fastPeriod, slowPeriod, signalPeriod int) bool {
-	if len(closePrices) < fastPeriod {
-		return true
-	}
-
-	DIF, _, histogram := CalculateMACD(closePrices, fastPeriod, slowPeriod, signalPeriod)
-	if len(histogram) < 2 || len(DIF) < 2 {
-		return true
-	}
-
-	E := histogram[len(histogram)-1]
-	D := histogram[len(histogram)-2]
-
-	DIFPRE := DIF[len(DIF)-1]
-	DIFPRE2 := DIF[len(DIF)-2]
-
-	//前者大
-	if E > D && DIFPRE > DIFPRE2 {
-		return true
-	}
-
-	return false
-}
-func ColANDDIFUPMicro(closePrices []float64, fastPeriod, slowPeriod, signalPeriod int) bool {
-	if len(closePrices) < fastPeriod {
-		return true
-	}
-
-	DIF, _, histogram := CalculateMACD(closePrices, fastPeriod, slowPeriod, signalPeriod)
-	if len(histogram) < 3 || len(DIF) < 3 {
-		return true
-	}
-
-	E := histogram[len(histogram)-2]
-	D := histogram[len(histogram)-3]
-
-	DIFPRE := DIF[len(DIF)-2]
-	DIFPRE2 := DIF[len(DIF)-3]
-
-	//前者大
-	if E > D && DIFPRE > DIFPRE2 {
-		return true
-	}
-
-	return false
+// MACDOf 对一段收盘价只计算一次 MACD，同一序列的各判断共用
+func MACDOf(closePrices []float64, fastPeriod, slowPeriod, signalPeriod int) *indicator.MACD {
+	return indicator.NewMACD(fastPeriod, slowPeriod, signalPeriod).Seed(closePrices)
 }
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"math"
+	"onchain-energe-SRSI/indicator"
+	"testing"
+)
+
+// wave 有涨有跌的收盘价
+func wave(n int) []float64 {
+	c := make([]float64, n)
+	for i := range c {
+		c[i] = 100 + 10*math.Sin(float64(i)/3) + float64(i)*0.2
+	}
+	return c
+}
+
+func near(a, b float64) bool { return math.Abs(a-b) <= 1e-9 }
+
+// 指标包与原有 CalculateEMA / CalculateMACD 在各种长度下逐根一致，包括少于慢线周期的 6~12 根
+func TestIndicatorMatchesCalculateMACD(t *testing.T) {
+	for n := 1; n <= 40; n++ {
+		closes := wave(n)
+		dif, _, hist := CalculateMACD(closes, 6, 13, 5)
+		m := MACDOf(closes, 6, 13, 5)
+		sDIF, sDEA, sHist := indicator.MACDSeries(closes, 6, 13, 5)
+		for i := 0; i < n; i++ {
+			ago := n - 1 - i
+			if !near(m.DIF(ago), dif[i]) || !near(m.Hist(ago), hist[i]) {
+				t.Fatalf("n=%d 第 %d 根: DIF %v / %v, 柱值 %v / %v", n, i, m.DIF(ago), dif[i], m.Hist(ago), hist[i])
+			}
+			if !near(sDIF[i], dif[i]) || !near(sHist[i], hist[i]) || !near(sDIF[i]-sDEA[i], sHist[i]) {
+				t.Fatalf("n=%d 第 %d 根: MACDSeries 与 CalculateMACD 不一致", n, i)
+			}
+		}
+		if _, dea, _ := m.Value(); n > 0 && !near(dea, sDEA[n-1]) {
+			t.Errorf("n=%d DEA = %v, want %v", n, dea, sDEA[n-1])
+		}
+	}
+}
+
+func TestEMASeriesMatchesCalculateEMA(t *testing.T) {
+	// CalculateEMA 对单个数据返回 0，EMASeries 返回该值本身，从 2 根起比较
+	for _, period := range []int{5, 6, 13, 25} {
+		for n := 2; n <= 40; n++ {
+			closes := wave(n)
+			want, last := CalculateEMA(closes, period)
+			got := indicator.EMASeries(closes, period)
+			for i := range want {
+				if !near(got[i], want[i]) {
+					t.Fatalf("period=%d n=%d 第 %d 根: %v, want %v", period, n, i, got[i], want[i])
+				}
+			}
+			if e := indicator.NewEMA(period).Seed(closes); !near(e.Value(), last) {
+				t.Errorf("period=%d n=%d Seed 后 = %v, want %v", period, n, e.Value(), last)
+			}
+		}
+	}
+}
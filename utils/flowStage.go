@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"math"
+	"onchain-energe-SRSI/flow"
+	"onchain-energe-SRSI/types"
+)
+
+// FlowStage 5m 成交量确认：最近收盘 K 线放量达到基线均量的 MinSpike 倍，买单占比不低于 MinBuyRatio。
+// 未启用时 ok 为 false；short 为 true 时改为要求卖单占比
+func FlowStage(series types.Series, item types.TokenItem, cfg types.FlowConfig, short bool) (st types.StageDiag, stats flow.Stats, ok bool) {
+	if cfg.MinSpike <= 0 && cfg.MinBuyRatio <= 0 {
+		return st, stats, false
+	}
+	i, _ := series.Index(types.BarLastClosed)
+	stats = flow.Compute(item.Buys, item.Sells, series.Volume, i, cfg.BaselineBars)
+	st = types.StageDiag{Stage: "flow", Passed: true, Values: map[string]float64{
+		"volume":    stats.Volume,
+		"baseline":  stats.Baseline,
+		"spike":     stats.Spike,
+		"buy_ratio": stats.BuyRatio,
+	}}
+	if cfg.MinSpike > 0 && !(stats.Spike >= cfg.MinSpike) {
+		st.Passed = false
+	}
+	// 没有买卖笔数（如手动关注的代币）时不做该项判断
+	if cfg.MinBuyRatio > 0 && !math.IsNaN(stats.BuyRatio) {
+		side := stats.BuyRatio
+		if short {
+			side = 1 - side
+		}
+		if side < cfg.MinBuyRatio {
+			st.Passed = false
+		}
+	}
+	return st, stats, true
+}
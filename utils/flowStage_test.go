@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/types"
+	"testing"
+)
+
+func TestFlowStage(t *testing.T) {
+	// 最近收盘 K 线放量 6 倍
+	series := types.Series{Close: []float64{1, 1, 1, 1}, Volume: []float64{1, 2, 3, 12}}
+	cfg := types.FlowConfig{MinSpike: 2, BaselineBars: 3, MinBuyRatio: 0.6}
+	tests := []struct {
+		name  string
+		buys  int
+		sells int
+		short bool
+		want  bool
+	}{
+		{"多头买单占比达标", 70, 30, false, true},
+		{"多头买单占比不足", 30, 70, false, false},
+		{"空头按卖单占比判断", 30, 70, true, true},
+		{"空头卖单占比不足", 70, 30, true, false},
+		{"全是买单时空头不通过", 10, 0, true, false},
+		{"全是卖单时空头通过", 0, 10, true, true},
+		{"没有买卖笔数时跳过占比", 0, 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _, ok := FlowStage(series, types.TokenItem{Buys: tt.buys, Sells: tt.sells}, cfg, tt.short)
+			if !ok {
+				t.Fatal("已设置阈值时 ok 应为 true")
+			}
+			if st.Passed != tt.want {
+				t.Errorf("Passed = %v, want %v", st.Passed, tt.want)
+			}
+		})
+	}
+
+	// 放量不足时无论方向都不通过
+	quiet := types.Series{Close: []float64{1, 1, 1, 1}, Volume: []float64{1, 2, 3, 3}}
+	if st, _, _ := FlowStage(quiet, types.TokenItem{Buys: 0, Sells: 10}, cfg, true); st.Passed {
+		t.Error("量比 1.5 < 2 时不应通过")
+	}
+	if _, _, ok := FlowStage(series, types.TokenItem{}, types.FlowConfig{}, false); ok {
+		t.Error("未设置阈值时 ok 应为 false")
+	}
+}
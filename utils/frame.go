@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"math"
+	"onchain-energe-SRSI/indicator"
+	"onchain-energe-SRSI/types"
+)
+
+// Frame 一个周期的 K 线与 MACD(6,13,5)，MACD 只计算一次；所有读取与判断都按柱偏移（types.BarForming / types.BarLastClosed）
+type Frame struct {
+	Series types.Series
+	MACD   *indicator.MACD
+}
+
+func NewFrame(series types.Series) *Frame {
+	return &Frame{Series: series, MACD: MACDOf(series.Close, 6, 13, 5)}
+}
+
+// ago 偏移对应的 MACD 历史位置（相对最后一次 Update）
+func (f *Frame) ago(offset int) (int, bool) {
+	i, ok := f.Series.Index(offset)
+	return f.Series.Len() - 1 - i, ok
+}
+
+// Price 该柱收盘价，不存在时为 NaN
+func (f *Frame) Price(offset int) float64 {
+	i, ok := f.Series.Index(offset)
+	if !ok {
+		return math.NaN()
+	}
+	return f.Series.Close[i]
+}
+
+// EMA 截至该柱（含）的 EMA
+func (f *Frame) EMA(period, offset int) float64 {
+	i, ok := f.Series.Index(offset)
+	if !ok {
+		return math.NaN()
+	}
+	_, v := CalculateEMA(f.Series.Close[:i+1], period)
+	return v
+}
+
+// MA 截至该柱（含）的简单均线
+func (f *Frame) MA(period, offset int) float64 {
+	i, ok := f.Series.Index(offset)
+	if !ok {
+		return math.NaN()
+	}
+	return CalculateMA(f.Series.Close[:i+1], period)
+}
+
+// DIF 该柱的 DIF
+func (f *Frame) DIF(offset int) float64 {
+	a, _ := f.ago(offset)
+	return f.MACD.DIF(a)
+}
+
+// Hist 该柱的柱值
+func (f *Frame) Hist(offset int) float64 {
+	a, _ := f.ago(offset)
+	return f.MACD.Hist(a)
+}
+
+// bars 截至该柱（含）的 K 线数
+func (f *Frame) bars(offset int) int {
+	i, ok := f.Series.Index(offset)
+	if !ok {
+		return 0
+	}
+	return i + 1
+}
+
+// DIFUp 该柱 DIF 大于 0；数据不足快线周期时视为通过，该柱不存在（如未取到 K 线）时不通过
+func (f *Frame) DIFUp(offset int) bool {
+	n := f.bars(offset)
+	if n == 0 {
+		return false
+	}
+	if n < f.MACD.FastPeriod() {
+		return true
+	}
+	return f.DIF(offset) > 0
+}
+
+// DIFDown 该柱 DIF 小于 0；数据不足时不通过
+func (f *Frame) DIFDown(offset int) bool {
+	if f.bars(offset) < f.MACD.FastPeriod() {
+		return false
+	}
+	return f.DIF(offset) < 0
+}
+
+// Rising 该柱的柱值与 DIF 都高于前一柱；数据不足时视为通过，该柱不存在时不通过
+func (f *Frame) Rising(offset int) bool {
+	n := f.bars(offset)
+	if n == 0 {
+		return false
+	}
+	if n < max(f.MACD.FastPeriod(), 2) {
+		return true
+	}
+	//前者大
+	return f.Hist(offset) > f.Hist(offset+1) && f.DIF(offset) > f.DIF(offset+1)
+}
+
+// Falling 与 Rising 对称；数据不足时不通过
+func (f *Frame) Falling(offset int) bool {
+	if f.bars(offset) < max(f.MACD.FastPeriod(), 2) {
+		return false
+	}
+	//前者小
+	return f.Hist(offset) < f.Hist(offset+1) && f.DIF(offset) < f.DIF(offset+1)
+}
+
+// CrossDown DIF 在该柱下穿零轴（前一柱不小于 0）；数据不足时不通过
+func (f *Frame) CrossDown(offset int) bool {
+	if f.bars(offset) < max(f.MACD.FastPeriod(), 2) {
+		return false
+	}
+	return f.DIF(offset) < 0 && f.DIF(offset+1) >= 0
+}
+
+// HistFalling 从该柱往前连续 n 根柱值依次下降
+func (f *Frame) HistFalling(offset, n int) bool {
+	if f.bars(offset) < n+1 {
+		return false
+	}
+	for k := offset; k < offset+n; k++ {
+		if !(f.Hist(k) < f.Hist(k+1)) {
+			return false
+		}
+	}
+	return true
+}
+
+// MACDValues 诊断输出用
+func (f *Frame) MACDValues(offset int) map[string]float64 {
+	return map[string]float64{"dif": f.DIF(offset), "hist": f.Hist(offset)}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/types"
+	"testing"
+)
+
+// ramp 先涨 up 根、再跌 down 根的收盘价
+func ramp(up, down int) []float64 {
+	var c []float64
+	p := 100.0
+	for i := 0; i < up; i++ {
+		p++
+		c = append(c, p)
+	}
+	for i := 0; i < down; i++ {
+		p--
+		c = append(c, p)
+	}
+	return c
+}
+
+// closed 全部为已收盘 K 线的序列，BarLastClosed 即最后一根
+func closed(c []float64) *Frame {
+	return NewFrame(types.Series{Close: c})
+}
+
+func TestFrameCrossDownOnlyOnCrossBar(t *testing.T) {
+	closes := ramp(40, 30)
+	n := 0
+	for i := 14; i <= len(closes); i++ {
+		if closed(closes[:i]).CrossDown(types.BarLastClosed) {
+			n = i
+			break
+		}
+	}
+	if n == 0 {
+		t.Fatal("序列中没有 DIF 下穿")
+	}
+	for i := n + 1; i <= len(closes); i++ {
+		f := closed(closes[:i])
+		if f.DIF(types.BarLastClosed) >= 0 {
+			t.Fatalf("第 %d 根 DIF 应持续为负", i)
+		}
+		if f.CrossDown(types.BarLastClosed) {
+			t.Errorf("第 %d 根 DIF 持续为负，不应再次算作下穿", i)
+		}
+	}
+	if closed(closes[:5]).CrossDown(types.BarLastClosed) {
+		t.Error("数据不足时不应触发")
+	}
+}
+
+func TestFrameDIFUpRising(t *testing.T) {
+	up, down := accel(30, 1), ramp(30, 10)
+	tests := []struct {
+		name       string
+		frame      *Frame
+		wantDIFUp  bool
+		wantRising bool
+	}{
+		{"空序列不通过", closed(nil), false, false},
+		{"只有形成中的一根", NewFrame(types.Series{Close: []float64{1}, Forming: true}), false, false},
+		{"数据不足快线周期视为通过", closed(up[:3]), true, true},
+		{"加速上涨", closed(up), true, true},
+		{"上涨后回落：DIF 仍为正但走弱", closed(down[:33]), true, false},
+		{"持续下跌后", closed(down), false, false},
+		// 形成中的一根大跌不影响已收盘柱的判断
+		{"形成中的一根不计入", NewFrame(types.Series{Close: append(append([]float64{}, up...), 50), Forming: true}), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.frame.DIFUp(types.BarLastClosed); got != tt.wantDIFUp {
+				t.Errorf("DIFUp = %v, want %v (DIF=%v)", got, tt.wantDIFUp, tt.frame.DIF(types.BarLastClosed))
+			}
+			if got := tt.frame.Rising(types.BarLastClosed); got != tt.wantRising {
+				t.Errorf("Rising = %v, want %v", got, tt.wantRising)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"math"
+	"onchain-energe-SRSI/indicator"
+	"onchain-energe-SRSI/types"
+)
+
+// GateStage 以 5m 已收盘 K 线评估波动率与成交量过滤；未配置任何过滤时 ok 为 false。
+// short 为 true 时方向类过滤（VWAP、ADX 方向、Supertrend、OBV）取反
+func GateStage(series types.Series, g types.GateConfig, short bool) (st types.StageDiag, ok bool) {
+	if !gatesEnabled(g) {
+		return st, false
+	}
+	st = types.StageDiag{Stage: "gates", Passed: true, Values: map[string]float64{}}
+	closed := series.Closed().Candles()
+	if len(closed) == 0 {
+		st.Passed = false
+		return st, true
+	}
+	price := closed[len(closed)-1].Close
+	fail := func(cond bool) {
+		if !cond {
+			st.Passed = false
+		}
+	}
+	// aligned 多头要求 v 为正，空头要求为负；NaN 视为未通过
+	aligned := func(v float64) bool {
+		if short {
+			return v < 0
+		}
+		return v > 0
+	}
+
+	if g.MinATRPct > 0 || g.MaxATRPct > 0 {
+		atrPct := indicator.Last(indicator.ATR(closed, 14)) / price * 100
+		st.Values["atr_pct"] = atrPct
+		fail(!math.IsNaN(atrPct))
+		fail(g.MinATRPct <= 0 || atrPct >= g.MinATRPct)
+		fail(g.MaxATRPct <= 0 || atrPct <= g.MaxATRPct)
+	}
+	if g.MaxBandWidthPct > 0 {
+		b := indicator.Bollinger(closed, 20, 2)
+		width := b.Width(len(closed)-1) * 100
+		st.Values["bb_width_pct"] = width
+		fail(!math.IsNaN(width) && width <= g.MaxBandWidthPct)
+	}
+	if g.VWAP {
+		var vwap float64
+		if g.RollingVWAPBars > 0 {
+			vwap = indicator.Last(indicator.RollingVWAP(closed, g.RollingVWAPBars))
+		} else {
+			vwap = indicator.Last(indicator.VWAP(closed))
+		}
+		st.Values["vwap"] = vwap
+		fail(aligned(price - vwap))
+	}
+	if g.MinADX > 0 {
+		d := indicator.ADX(closed, 14)
+		adx := indicator.Last(d.ADX)
+		st.Values["adx"] = adx
+		st.Values["plus_di"] = indicator.Last(d.PlusDI)
+		st.Values["minus_di"] = indicator.Last(d.MinusDI)
+		fail(adx >= g.MinADX && aligned(indicator.Last(d.PlusDI)-indicator.Last(d.MinusDI)))
+	}
+	if g.Supertrend {
+		t := indicator.Supertrend(closed, 10, 3)
+		line := indicator.Last(t.Line)
+		st.Values["supertrend"] = line
+		fail(!math.IsNaN(line) && t.Up[len(t.Up)-1] != short)
+	}
+	if g.OBVBars > 0 {
+		slope := indicator.Slope(indicator.OBV(closed), g.OBVBars)
+		st.Values["obv_slope"] = slope
+		fail(aligned(slope))
+	}
+	return st, true
+}
+
+// gatesEnabled 是否启用了任一过滤
+func gatesEnabled(g types.GateConfig) bool {
+	return g.MinATRPct > 0 || g.MaxATRPct > 0 || g.MaxBandWidthPct > 0 || g.VWAP || g.MinADX > 0 || g.Supertrend || g.OBVBars > 0
+}
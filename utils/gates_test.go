@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/types"
+	"testing"
+)
+
+// trendSeries n 根已收盘的 5m K 线加一根形成中，每根收盘价变化 step，成交量恒定
+func trendSeries(n int, step float64) types.Series {
+	s := types.Series{Timeframe: types.TimeframeMinute, Aggregate: 5, Forming: true}
+	for i := 0; i <= n; i++ {
+		c := 100 + step*float64(i)
+		s.Timestamps = append(s.Timestamps, int64(i)*300)
+		s.Open = append(s.Open, c-step)
+		s.High = append(s.High, c+0.5)
+		s.Low = append(s.Low, c-0.5)
+		s.Close = append(s.Close, c)
+		s.Volume = append(s.Volume, 10)
+	}
+	// 形成中的一根反向大幅波动，不应影响判断
+	s.Close[n] = 100 - step*float64(n)
+	return s
+}
+
+func TestGateStageDirection(t *testing.T) {
+	up, down := trendSeries(40, 1), trendSeries(40, -1)
+	tests := []struct {
+		name   string
+		gates  types.GateConfig
+		series types.Series
+		short  bool
+		want   bool
+	}{
+		{"VWAP：上涨做多通过", types.GateConfig{VWAP: true}, up, false, true},
+		{"VWAP：上涨做空不通过", types.GateConfig{VWAP: true}, up, true, false},
+		{"VWAP：下跌做空通过", types.GateConfig{VWAP: true}, down, true, true},
+		{"滚动 VWAP：下跌做多不通过", types.GateConfig{VWAP: true, RollingVWAPBars: 10}, down, false, false},
+		{"ADX：上涨做多通过", types.GateConfig{MinADX: 20}, up, false, true},
+		{"ADX：上涨做空不通过", types.GateConfig{MinADX: 20}, up, true, false},
+		{"ADX：下跌做空通过", types.GateConfig{MinADX: 20}, down, true, true},
+		{"Supertrend：上涨做多通过", types.GateConfig{Supertrend: true}, up, false, true},
+		{"Supertrend：下跌做多不通过", types.GateConfig{Supertrend: true}, down, false, false},
+		{"Supertrend：下跌做空通过", types.GateConfig{Supertrend: true}, down, true, true},
+		{"OBV：上涨做多通过", types.GateConfig{OBVBars: 5}, up, false, true},
+		{"OBV：上涨做空不通过", types.GateConfig{OBVBars: 5}, up, true, false},
+		{"OBV：下跌做空通过", types.GateConfig{OBVBars: 5}, down, true, true},
+		// 波动率类过滤与方向无关
+		{"ATR 上限：做多", types.GateConfig{MaxATRPct: 5}, up, false, true},
+		{"ATR 上限：做空同样通过", types.GateConfig{MaxATRPct: 5}, up, true, true},
+		{"ATR 下限过高：做空同样不通过", types.GateConfig{MinATRPct: 50}, down, true, false},
+		{"全部启用：下跌做空通过", types.GateConfig{VWAP: true, MinADX: 20, Supertrend: true, OBVBars: 5, MaxATRPct: 5}, down, true, true},
+		{"全部启用：下跌做多不通过", types.GateConfig{VWAP: true, MinADX: 20, Supertrend: true, OBVBars: 5, MaxATRPct: 5}, down, false, false},
+		{"数据不足：Supertrend 不通过", types.GateConfig{Supertrend: true}, trendSeries(5, 1), false, false},
+		{"空序列不通过", types.GateConfig{VWAP: true}, types.Series{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := GateStage(tt.series, tt.gates, tt.short)
+			if !ok {
+				t.Fatal("已启用过滤时 ok 应为 true")
+			}
+			if st.Passed != tt.want {
+				t.Errorf("Passed = %v, want %v (values=%v)", st.Passed, tt.want, st.Values)
+			}
+		})
+	}
+
+	if _, ok := GateStage(up, types.GateConfig{}, false); ok {
+		t.Error("未配置过滤时 ok 应为 false")
+	}
+}
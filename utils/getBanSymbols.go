@@ -5,14 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/http"
+	"onchain-energe-SRSI/logging"
+	"onchain-energe-SRSI/metrics"
 	"time"
 )
 
 // 从 ban 服务返回的内容是一个字符串数组，例如：["BTCUSDT","ETHUSDT"]
-func GetBanList() []string {
+func GetBanList(parent context.Context) []string {
 	const (
 		baseURL        = "http://127.0.0.1:9001/dex/ban/list"
 		maxRetries     = 3
@@ -20,6 +21,7 @@ func GetBanList() []string {
 	)
 
 	var lastErr error
+	logger := logging.Component("banlist")
 	client := &http.Client{
 		Timeout: requestTimeout,
 		Transport: &http.Transport{
@@ -38,15 +40,21 @@ func GetBanList() []string {
 	backoff := time.Second // 初始退避时间 1 秒
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		if attempt > 1 {
+			if SleepContext(parent, backoff) != nil {
+				break
+			}
+			metrics.Retries.Inc(metrics.ServiceBanList)
+		}
+		backoff *= 2
+
+		ctx, cancel := context.WithTimeout(parent, requestTimeout)
 		defer cancel()
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
 		if err != nil {
 			lastErr = fmt.Errorf("build request error: %v", err)
-			log.Printf("[GetBanList] attempt %d build request error: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
+			logger.Warn("构造请求失败", "attempt", attempt, "err", err)
 			continue
 		}
 
@@ -54,12 +62,12 @@ func GetBanList() []string {
 		req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Go-http-client)")
 		req.Header.Set("Accept", "application/json")
 
+		start := time.Now()
 		resp, err := client.Do(req)
+		metrics.ObserveRequest(metrics.ServiceBanList, start, resp, err)
 		if err != nil {
 			lastErr = fmt.Errorf("request error: %v", err)
-			log.Printf("[GetBanList] attempt %d request error: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
+			logger.Warn("请求失败", "attempt", attempt, "err", err)
 			continue
 		}
 
@@ -67,26 +75,20 @@ func GetBanList() []string {
 		resp.Body.Close()
 		if err != nil {
 			lastErr = fmt.Errorf("read body error: %v", err)
-			log.Printf("[GetBanList] attempt %d read body error: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
+			logger.Warn("读取响应失败", "attempt", attempt, "err", err)
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
 			lastErr = fmt.Errorf("http status %d body: %s", resp.StatusCode, string(body))
-			log.Printf("[GetBanList] attempt %d http error: %v", attempt, lastErr)
-			time.Sleep(backoff)
-			backoff *= 2
+			logger.Warn("HTTP 状态码错误", "attempt", attempt, "err", lastErr)
 			continue
 		}
 
 		var symbols []string
 		if err := json.Unmarshal(body, &symbols); err != nil {
 			lastErr = fmt.Errorf("json unmarshal error: %v, body=%s", err, string(body))
-			log.Printf("[GetBanList] attempt %d json unmarshal error: %v", attempt, lastErr)
-			time.Sleep(backoff)
-			backoff *= 2
+			logger.Warn("解析响应失败", "attempt", attempt, "err", lastErr)
 			continue
 		}
 
@@ -94,31 +96,36 @@ func GetBanList() []string {
 		return symbols
 	}
 
-	log.Printf("[GetBanList] failed after %d attempts, last error: %v", maxRetries, lastErr)
+	logger.Error("多次尝试后获取封禁列表失败", "attempts", maxRetries, "err", lastErr)
 	return []string{}
 }
 
-// 定期获取 ban 列表并发送到 channel
-func StartBanListFetcher(ch chan<- []string) {
+// 定期获取 ban 列表并发送到 channel，ctx 取消后停止
+func StartBanListFetcher(ctx context.Context, ch chan<- []string) {
 	go func() {
 		// 启动时立即取一次
-		symbols := GetBanList()
+		symbols := GetBanList(ctx)
 		select {
 		case ch <- symbols:
 		default:
-			fmt.Println("Warning: channel blocked, skipping first update")
+			logging.Component("banlist").Warn("channel 已满，跳过首次更新")
 		}
 
 		// 每隔 1 分钟取一次
 		ticker := time.NewTicker(1 * time.Minute)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			symbols := GetBanList()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			symbols := GetBanList(ctx)
 			select {
 			case ch <- symbols:
 			default:
-				fmt.Println("Warning: channel blocked, skipping update")
+				logging.Component("banlist").Warn("channel 已满，跳过本次更新")
 			}
 		}
 	}()
@@ -1,11 +1,13 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"onchain-energe-SRSI/metrics"
 	"onchain-energe-SRSI/types"
 	"time"
 )
@@ -30,14 +32,14 @@ type AxiomToken struct {
 	Discord          string  `json:"discord"`
 }
 
-// FetchRankData 从 Axiom API 拉取数据并转换为 TokenItem
-func FetchRankData(fetchURL string, proxy string) ([]*types.TokenItem, error) {
+// FetchRankData 从 Axiom API 拉取数据，按 filters 过滤后转换为 TokenItem
+func FetchRankData(ctx context.Context, fetchURL string, proxy string, filters types.FilterConfig) ([]*types.TokenItem, error) {
 	if fetchURL == "" {
 		return nil, fmt.Errorf("url is empty")
 	}
 
 	// 构建请求
-	req, err := http.NewRequest("GET", fetchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fetchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %v", err)
 	}
@@ -61,7 +63,9 @@ func FetchRankData(fetchURL string, proxy string) ([]*types.TokenItem, error) {
 	}
 
 	// 发送请求
+	start := time.Now()
 	resp, err := client.Do(req)
+	metrics.ObserveRequest(metrics.ServiceAxiom, start, resp, err)
 	if err != nil {
 		return nil, fmt.Errorf("发送请求失败: %v", err)
 	}
@@ -85,8 +89,10 @@ func FetchRankData(fetchURL string, proxy string) ([]*types.TokenItem, error) {
 	// 转换成内部结构
 	var tokenList []*types.TokenItem
 	for _, at := range axiomTokens {
-		// 市值 > 1000 SOL, 持币人数 > 1000, SOL流动性 > 250, TOP10 < 25, BUYCOUNT > 20
-		if at.MarketCapSol < 1000 || at.NumHolders < 1000 || at.LiquiditySol < 250 || at.Top10Holders > 25 || at.BuyCount < 20 {
+		// 默认: 市值 > 1000 SOL, 持币人数 > 1000, SOL流动性 > 250, TOP10 < 25, BUYCOUNT > 20
+		if at.MarketCapSol < filters.MinMarketCapSol || at.NumHolders < filters.MinHolders ||
+			at.LiquiditySol < filters.MinLiquiditySol || at.Top10Holders > filters.MaxTop10Holders ||
+			at.BuyCount < filters.MinBuyCount {
 			continue
 		}
 
@@ -101,7 +107,8 @@ func FetchRankData(fetchURL string, proxy string) ([]*types.TokenItem, error) {
 			HolderCount:     at.NumHolders,
 			Top10Hoders:     at.Top10Holders,
 			Buys:            at.BuyCount,
-			Sells:           0,
+			Sells:           max(at.TransactionCount-at.BuyCount, 0),
+			BuyCount:        at.BuyCount,
 			PoolAddress:     at.PairAddress,
 			Website:         at.Website,
 			TwitterUsername: at.Twitter,
@@ -110,5 +117,6 @@ func FetchRankData(fetchURL string, proxy string) ([]*types.TokenItem, error) {
 		tokenList = append(tokenList, item)
 	}
 
+	metrics.ObserveScanTokens("filtered", len(axiomTokens)-len(tokenList))
 	return tokenList, nil
 }
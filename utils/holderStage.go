@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"fmt"
+	"onchain-energe-SRSI/types"
+)
+
+// HolderStage 按持币人数、前十占比、聪明钱与持币增长过滤，在 4h 之前执行，不请求 K 线；未设置任何阈值时 ok 为 false
+func HolderStage(item types.TokenItem, growth types.HolderGrowth, cfg types.HolderConfig) (st types.StageDiag, ok bool) {
+	if !cfg.Enabled || (cfg.MinHolders <= 0 && cfg.MaxTop10Pct <= 0 && cfg.MinSmartDegen <= 0 && cfg.MinRenowned <= 0 && cfg.MinGrowthPct <= 0) {
+		return st, false
+	}
+	st = types.StageDiag{Stage: "holders", Passed: true, Values: map[string]float64{
+		"holders":     float64(item.HolderCount),
+		"top10_pct":   item.Top10Hoders,
+		"smart_degen": float64(item.SmartDegenCount),
+		"renowned":    float64(item.RenownedCount),
+	}}
+	fail := func(cond bool) {
+		if !cond {
+			st.Passed = false
+		}
+	}
+	fail(cfg.MinHolders <= 0 || item.HolderCount >= cfg.MinHolders)
+	// 占比未知（0）时视为未通过
+	fail(cfg.MaxTop10Pct <= 0 || (item.Top10Hoders > 0 && item.Top10Hoders <= cfg.MaxTop10Pct))
+	fail(cfg.MinSmartDegen <= 0 || item.SmartDegenCount >= cfg.MinSmartDegen)
+	fail(cfg.MinRenowned <= 0 || item.RenownedCount >= cfg.MinRenowned)
+	// 增长未知（窗口内没有更早的记录）时跳过该项，首次出现的代币只按其余条件判断
+	if growth.Known {
+		st.Values["growth_pct"] = growth.Pct
+		fail(cfg.MinGrowthPct <= 0 || growth.Pct >= cfg.MinGrowthPct)
+	}
+	return st, true
+}
+
+// FormatHolders 告警中附带的持币信息
+func FormatHolders(item types.TokenItem, growth types.HolderGrowth) string {
+	line := fmt.Sprintf("👥 持币 %d", item.HolderCount)
+	if growth.Known {
+		line += fmt.Sprintf("（%+.1f%%）", growth.Pct)
+	}
+	if item.Top10Hoders > 0 {
+		line += fmt.Sprintf(" | 前十 %.1f%%", item.Top10Hoders)
+	}
+	if item.SmartDegenCount > 0 || item.RenownedCount > 0 {
+		line += fmt.Sprintf(" | 聪明钱 %d | 知名 %d", item.SmartDegenCount, item.RenownedCount)
+	}
+	return line
+}
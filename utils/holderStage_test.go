@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/types"
+	"testing"
+)
+
+func TestHolderStageGrowth(t *testing.T) {
+	cfg := types.HolderConfig{Enabled: true, MinHolders: 100, MinGrowthPct: 5}
+	item := types.TokenItem{HolderCount: 500}
+	tests := []struct {
+		name   string
+		item   types.TokenItem
+		growth types.HolderGrowth
+		want   bool
+	}{
+		{"增长达标", item, types.HolderGrowth{Known: true, Pct: 8}, true},
+		{"增长不足", item, types.HolderGrowth{Known: true, Pct: 2}, false},
+		{"增长未知（首次出现）跳过增长条件", item, types.HolderGrowth{}, true},
+		{"增长未知时其余条件照常判断", types.TokenItem{HolderCount: 50}, types.HolderGrowth{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := HolderStage(tt.item, tt.growth, cfg)
+			if !ok {
+				t.Fatal("已设置阈值时 ok 应为 true")
+			}
+			if st.Passed != tt.want {
+				t.Errorf("Passed = %v, want %v", st.Passed, tt.want)
+			}
+			if _, has := st.Values["growth_pct"]; has != tt.growth.Known {
+				t.Errorf("growth_pct 是否输出 = %v, want %v", has, tt.growth.Known)
+			}
+		})
+	}
+}
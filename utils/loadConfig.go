@@ -6,6 +6,8 @@ import (
 	"io"
 	"onchain-energe-SRSI/types"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // loadConfig 从文件加载配置
@@ -37,5 +39,236 @@ func LoadConfig(filePath string) (*types.Config, error) {
 		config.RSIPeriod = 14
 	}
 
+	h := &config.HTTP
+	if h.Listen == "" {
+		h.Listen = ":8889"
+	}
+	if h.ReadHeaderTimeoutSec <= 0 {
+		h.ReadHeaderTimeoutSec = 5
+	}
+	if h.ReadTimeoutSec <= 0 {
+		h.ReadTimeoutSec = 15
+	}
+	if h.WriteTimeoutSec <= 0 {
+		h.WriteTimeoutSec = 30
+	}
+	if h.IdleTimeoutSec <= 0 {
+		h.IdleTimeoutSec = 120
+	}
+	if config.ShutdownTimeoutSec <= 0 {
+		config.ShutdownTimeoutSec = 30
+	}
+	if config.ScanTimeoutSec <= 0 {
+		config.ScanTimeoutSec = 55
+	}
+	if config.TokenTimeoutSec <= 0 {
+		config.TokenTimeoutSec = 20
+	}
+	if config.Log.File == "" {
+		config.Log.File = filepath.Join(config.DataDir, "logs", "scanner.log")
+	}
+	if config.Log.MaxSizeMB <= 0 {
+		config.Log.MaxSizeMB = 20
+	}
+	if config.Log.MaxBackups <= 0 {
+		config.Log.MaxBackups = 5
+	}
+	pc := &config.Paper
+	if pc.StartingBalance <= 0 {
+		pc.StartingBalance = 100
+	}
+	if pc.PositionSize <= 0 {
+		pc.PositionSize = 1
+	}
+	if pc.MaxPositions <= 0 {
+		pc.MaxPositions = 10
+	}
+	// 以下各项可显式配置为 0（不收手续费 / 不启用该平仓规则），只在未填写或为负数时取默认值
+	paperSet, err := presentKeys(data, "paper")
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
+	if !paperSet["fee_pct"] || pc.FeePct < 0 {
+		pc.FeePct = 1
+	}
+	if !paperSet["min_slippage_pct"] || pc.MinSlippagePct < 0 {
+		pc.MinSlippagePct = 0.5
+	}
+	if !paperSet["take_profit_pct"] || pc.TakeProfitPct < 0 {
+		pc.TakeProfitPct = 30
+	}
+	if !paperSet["stop_loss_pct"] || pc.StopLossPct < 0 {
+		pc.StopLossPct = 15
+	}
+	if !paperSet["trailing_stop_pct"] || pc.TrailingStopPct < 0 {
+		pc.TrailingStopPct = 10
+	}
+	if pc.CheckIntervalSec <= 0 {
+		pc.CheckIntervalSec = 60
+	}
+	if pc.LedgerFile == "" {
+		pc.LedgerFile = filepath.Join(config.DataDir, "paper_ledger.json")
+	}
+	hc := &config.Health
+	if hc.AxiomStaleSec <= 0 {
+		hc.AxiomStaleSec = 300
+	}
+	if hc.GeckoStaleSec <= 0 {
+		hc.GeckoStaleSec = 300
+	}
+	if hc.BanListStaleSec <= 0 {
+		hc.BanListStaleSec = 300
+	}
+	if hc.TelegramStaleSec <= 0 {
+		hc.TelegramStaleSec = 86400
+	}
+	if hc.ScanStaleSec <= 0 {
+		hc.ScanStaleSec = 300
+	}
+	if hc.DiscoveryFailAlert <= 0 {
+		hc.DiscoveryFailAlert = 5
+	}
+	if config.Exit.WindowMin <= 0 {
+		config.Exit.WindowMin = 240
+	}
+	if config.Exit.CooldownMin <= 0 {
+		config.Exit.CooldownMin = 30
+	}
+	if config.Perp.OverrideFile == "" {
+		config.Perp.OverrideFile = filepath.Join(config.DataDir, "perp_overrides.json")
+	}
+	if config.Perp.RefreshMin <= 0 {
+		config.Perp.RefreshMin = 60
+	}
+	ec := &config.Execution
+	if ec.Venue == "" {
+		ec.Venue = "dry_run"
+	}
+	if ec.PositionSize <= 0 {
+		ec.PositionSize = 10
+	}
+	if ec.MaxPositions <= 0 {
+		ec.MaxPositions = 3
+	}
+	if ec.CooldownMin <= 0 {
+		ec.CooldownMin = 60
+	}
+	if ec.MaxHoldMin <= 0 {
+		ec.MaxHoldMin = 240
+	}
+	if ec.Jupiter.BaseURL == "" {
+		ec.Jupiter.BaseURL = "https://quote-api.jup.ag/v6"
+	}
+	if ec.Jupiter.SlippageBps <= 0 {
+		ec.Jupiter.SlippageBps = 100
+	}
+	if len(config.Holders.Providers) == 0 {
+		config.Holders.Providers = []string{"fixture", "axiom"}
+	}
+	if config.Holders.FixtureFile == "" {
+		config.Holders.FixtureFile = filepath.Join(config.DataDir, "holders_fixture.json")
+	}
+	if config.Holders.RPCURL == "" {
+		config.Holders.RPCURL = "https://api.mainnet-beta.solana.com"
+	}
+	if config.Holders.GrowthWindowMin <= 0 {
+		config.Holders.GrowthWindowMin = 60
+	}
+	if config.Flow.BaselineBars <= 0 {
+		config.Flow.BaselineBars = 20
+	}
+	st := &config.Settle
+	if st.InitialMs < 0 || st.MinMs < 0 || st.MaxMs < 0 {
+		return nil, fmt.Errorf("settle 等待时长不能为负数")
+	}
+	if st.InitialMs == 0 {
+		st.InitialMs = 10000
+	}
+	if st.MinMs == 0 {
+		st.MinMs = 2000
+	}
+	if st.MaxMs == 0 {
+		st.MaxMs = 30000
+	}
+	if st.MinMs > st.MaxMs {
+		return nil, fmt.Errorf("settle.min_ms (%d) 不能大于 settle.max_ms (%d)", st.MinMs, st.MaxMs)
+	}
+
+	// 过滤条件逐项取默认值：未填写的沿用原有阈值，显式填写的（下限可为 0）保留
+	filterSet, err := presentKeys(data, "filters")
+	if err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %v", err)
+	}
+	f := &config.Filters
+	if !filterSet["min_market_cap_sol"] {
+		f.MinMarketCapSol = 1000
+	}
+	if !filterSet["min_holders"] {
+		f.MinHolders = 1000
+	}
+	if !filterSet["min_liquidity_sol"] {
+		f.MinLiquiditySol = 250
+	}
+	if !filterSet["max_top10_holders"] {
+		f.MaxTop10Holders = 25
+	}
+	if !filterSet["min_buy_count"] {
+		f.MinBuyCount = 20
+	}
+	// 与管理接口 /api/admin/filters 相同的校验
+	if f.MinMarketCapSol < 0 || f.MinHolders < 0 || f.MinLiquiditySol < 0 || f.MinBuyCount < 0 {
+		return nil, fmt.Errorf("filters 阈值不能为负数")
+	}
+	if f.MaxTop10Holders <= 0 || f.MaxTop10Holders > 100 {
+		return nil, fmt.Errorf("filters.max_top10_holders 需在 (0, 100] 之间")
+	}
+	if config.Admin.AuditLog == "" {
+		config.Admin.AuditLog = filepath.Join(config.DataDir, "admin_audit.log")
+	}
+
+	if len(config.CommandChatIDs) == 0 && config.ChatID != "" {
+		config.CommandChatIDs = []string{config.ChatID}
+	}
+
+	q := &config.TelegramQueue
+	if q.GlobalPerSecond <= 0 {
+		q.GlobalPerSecond = 30
+	}
+	if q.PrivatePerSecond <= 0 {
+		q.PrivatePerSecond = 1
+	}
+	if q.GroupPerMinute <= 0 {
+		q.GroupPerMinute = 20
+	}
+	if q.CoalesceMs <= 0 {
+		q.CoalesceMs = 3000
+	}
+	if q.MaxDigest <= 0 {
+		q.MaxDigest = 10
+	}
+	if q.QueueSize <= 0 {
+		q.QueueSize = 1000
+	}
+
 	return &config, nil
 }
+
+// presentKeys 配置文件中 section 对象里出现的字段名，用于区分未填写与显式填 0
+func presentKeys(data []byte, section string) (map[string]bool, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	keys := map[string]bool{}
+	if len(raw[section]) == 0 || string(raw[section]) == "null" {
+		return keys, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw[section], &fields); err != nil {
+		return nil, err
+	}
+	for k := range fields {
+		keys[strings.ToLower(k)] = true // 与 encoding/json 一样不区分大小写
+	}
+	return keys, nil
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigPaperZeroDisables(t *testing.T) {
+	tests := []struct {
+		name                            string
+		paper                           string
+		fee, slip, takeProfit, stop, tr float64
+	}{
+		{"未填写时取默认值", `{}`, 1, 0.5, 30, 15, 10},
+		{"没有 paper 段", ``, 1, 0.5, 30, 15, 10},
+		{"显式 0 保留", `{"fee_pct":0,"min_slippage_pct":0,"take_profit_pct":0,"stop_loss_pct":0,"trailing_stop_pct":0}`, 0, 0, 0, 0, 0},
+		{"负数取默认值", `{"take_profit_pct":-1,"stop_loss_pct":5}`, 1, 0.5, 30, 5, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"data_dir":"` + t.TempDir() + `"}`
+			if tt.paper != "" {
+				body = `{"data_dir":"` + t.TempDir() + `","paper":` + tt.paper + `}`
+			}
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pc := cfg.Paper
+			got := []float64{pc.FeePct, pc.MinSlippagePct, pc.TakeProfitPct, pc.StopLossPct, pc.TrailingStopPct}
+			want := []float64{tt.fee, tt.slip, tt.takeProfit, tt.stop, tt.tr}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("got %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigPartialFilters(t *testing.T) {
+	defaults := types.FilterConfig{MinMarketCapSol: 1000, MinHolders: 1000, MinLiquiditySol: 250, MaxTop10Holders: 25, MinBuyCount: 20}
+	tests := []struct {
+		name    string
+		filters string
+		want    types.FilterConfig
+		wantErr bool
+	}{
+		{"没有 filters 段", ``, defaults, false},
+		{"只填一项，其余取默认值", `{"min_holders":300}`,
+			types.FilterConfig{MinMarketCapSol: 1000, MinHolders: 300, MinLiquiditySol: 250, MaxTop10Holders: 25, MinBuyCount: 20}, false},
+		{"下限显式 0 表示不限", `{"min_buy_count":0,"max_top10_holders":40}`,
+			types.FilterConfig{MinMarketCapSol: 1000, MinHolders: 1000, MinLiquiditySol: 250, MaxTop10Holders: 40, MinBuyCount: 0}, false},
+		{"前十占比为 0 拒绝", `{"max_top10_holders":0}`, types.FilterConfig{}, true},
+		{"前十占比超过 100 拒绝", `{"max_top10_holders":120}`, types.FilterConfig{}, true},
+		{"负数拒绝", `{"min_liquidity_sol":-1}`, types.FilterConfig{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"data_dir":"` + t.TempDir() + `"}`
+			if tt.filters != "" {
+				body = `{"data_dir":"` + t.TempDir() + `","filters":` + tt.filters + `}`
+			}
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("应拒绝 filters=%s", tt.filters)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Filters != tt.want {
+				t.Errorf("Filters = %+v, want %+v", cfg.Filters, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSettle(t *testing.T) {
+	tests := []struct {
+		name            string
+		settle          string
+		initial, lo, hi int
+		wantErr         bool
+	}{
+		{"未填写时取默认值", ``, 10000, 2000, 30000, false},
+		{"部分填写", `{"min_ms":500}`, 10000, 500, 30000, false},
+		{"最小值等于最大值", `{"min_ms":5000,"max_ms":5000}`, 10000, 5000, 5000, false},
+		{"最小值大于最大值拒绝", `{"min_ms":5000,"max_ms":1000}`, 0, 0, 0, true},
+		{"与默认最大值比较", `{"min_ms":40000}`, 0, 0, 0, true},
+		{"负数拒绝", `{"initial_ms":-1}`, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"data_dir":"` + t.TempDir() + `"}`
+			if tt.settle != "" {
+				body = `{"data_dir":"` + t.TempDir() + `","settle":` + tt.settle + `}`
+			}
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfig(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("应拒绝 settle=%s", tt.settle)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := cfg.Settle; s.InitialMs != tt.initial || s.MinMs != tt.lo || s.MaxMs != tt.hi {
+				t.Errorf("Settle = %+v, want %d/%d/%d", s, tt.initial, tt.lo, tt.hi)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"onchain-energe-SRSI/chart"
+	"onchain-energe-SRSI/geckoterminal"
+	"onchain-energe-SRSI/indicator"
+)
+
+// RenderSignalChart 绘制 5m 与 1h 图：K 线 + EMA25/MA60 + MACD(6,13,5)
+func RenderSignalChart(symbol string, m5, h1 []geckoterminal.OHLCV) ([]byte, error) {
+	return chart.RenderPNG(
+		signalPanel(symbol+" 5m", m5),
+		signalPanel(symbol+" 1h", h1),
+	)
+}
+
+func signalPanel(title string, candles []geckoterminal.OHLCV) chart.Panel {
+	closes := ClosesOf(candles)
+	ema25 := indicator.EMASeries(closes, 25)
+	dif, dea, hist := indicator.MACDSeries(closes, 6, 13, 5)
+	return chart.Panel{
+		Title:   title,
+		Candles: candles,
+		Overlays: []chart.Line{
+			{Label: "EMA25", Values: ema25, Color: chart.ColorEMA25},
+			{Label: "MA60", Values: CalculateMASeries(closes, 60), Color: chart.ColorMA60},
+		},
+		DIF:  dif,
+		DEA:  dea,
+		Hist: hist,
+	}
+}